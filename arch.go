@@ -0,0 +1,126 @@
+package srcinfo
+
+// builtinArchAliases maps each architecture name to the canonical name of its
+// group of aliases. It pairs the names used by dpkg and makepkg, the dpkg name
+// being canonical. It is never modified, NewArchAliases returns a copy that
+// may be extended.
+var builtinArchAliases = map[string]string{
+	"amd64":   "amd64",
+	"x86_64":  "amd64",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"i386":    "i386",
+	"i686":    "i386",
+	"armhf":   "armhf",
+	"armv7h":  "armhf",
+	"any":     "any",
+	"all":     "any",
+}
+
+// ArchAliases is a table of architecture names that mean the same
+// architecture, such as amd64 and x86_64. Set it in ParseOptions to use
+// aliases beyond the built in ones while parsing, merging and resolving.
+//
+// A nil *ArchAliases is valid and holds the built in aliases.
+type ArchAliases struct {
+	canonical map[string]string // arch -> canonical arch
+}
+
+// NewArchAliases returns an ArchAliases holding the built in aliases, which
+// pair the names used by dpkg and makepkg: amd64 and x86_64, arm64 and
+// aarch64, i386 and i686, armhf and armv7h, and any and all.
+func NewArchAliases() *ArchAliases {
+	aliases := &ArchAliases{canonical: make(map[string]string, len(builtinArchAliases))}
+
+	for arch, canonical := range builtinArchAliases {
+		aliases.canonical[arch] = canonical
+	}
+
+	return aliases
+}
+
+// Add makes every name an alias of the others.
+//
+// Names that already have aliases keep them, and the canonical name of the
+// first such group is kept, so Add("x64", "x86_64") makes amd64, x86_64 and
+// x64 all aliases of each other with amd64 remaining canonical. Otherwise the
+// first name given becomes canonical.
+func (aliases *ArchAliases) Add(names ...string) {
+	if len(names) == 0 {
+		return
+	}
+
+	canonical := names[0]
+	for _, name := range names {
+		if c, ok := aliases.canonical[name]; ok {
+			canonical = c
+			break
+		}
+	}
+
+	for _, name := range names {
+		old, ok := aliases.canonical[name]
+		if !ok {
+			aliases.canonical[name] = canonical
+			continue
+		}
+
+		// join the existing group of name into the new one
+		for alias, c := range aliases.canonical {
+			if c == old {
+				aliases.canonical[alias] = canonical
+			}
+		}
+	}
+}
+
+// Canonical returns the name used for arch and all of its aliases. Names
+// without aliases are returned unchanged.
+func (aliases *ArchAliases) Canonical(arch string) string {
+	table := builtinArchAliases
+	if aliases != nil {
+		table = aliases.canonical
+	}
+
+	if c, ok := table[arch]; ok {
+		return c
+	}
+
+	return arch
+}
+
+// Equal reports whether a and b name the same architecture.
+func (aliases *ArchAliases) Equal(a, b string) bool {
+	return a == b || aliases.Canonical(a) == aliases.Canonical(b)
+}
+
+// isAny reports whether arch means architecture independent.
+func (aliases *ArchAliases) isAny(arch string) bool {
+	return aliases.Equal(arch, "any")
+}
+
+// clone returns a copy of the aliases, nil staying nil.
+func (aliases *ArchAliases) clone() *ArchAliases {
+	if aliases == nil {
+		return nil
+	}
+
+	clone := &ArchAliases{canonical: make(map[string]string, len(aliases.canonical))}
+	for arch, canonical := range aliases.canonical {
+		clone.canonical[arch] = canonical
+	}
+
+	return clone
+}
+
+// CanonicalArch returns the name used for arch and all of its built in
+// aliases, see NewArchAliases. Names without aliases are returned unchanged.
+func CanonicalArch(arch string) string {
+	return (*ArchAliases)(nil).Canonical(arch)
+}
+
+// ArchEqual reports whether a and b name the same architecture using the
+// built in aliases.
+func ArchEqual(a, b string) bool {
+	return (*ArchAliases)(nil).Equal(a, b)
+}
@@ -0,0 +1,154 @@
+package srcinfo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestArchAliases(t *testing.T) {
+	pairs := [][2]string{
+		{"amd64", "x86_64"},
+		{"arm64", "aarch64"},
+		{"i386", "i686"},
+		{"armhf", "armv7h"},
+		{"any", "all"},
+	}
+
+	for _, pair := range pairs {
+		if !ArchEqual(pair[0], pair[1]) || !ArchEqual(pair[1], pair[0]) {
+			t.Errorf("%s and %s should be aliases", pair[0], pair[1])
+		}
+	}
+
+	if ArchEqual("amd64", "arm64") || ArchEqual("riscv64", "any") {
+		t.Error("unrelated arches should not be equal")
+	}
+
+	if CanonicalArch("riscv64") != "riscv64" {
+		t.Errorf("arches without aliases should be unchanged, got %s", CanonicalArch("riscv64"))
+	}
+
+	aliases := NewArchAliases()
+	aliases.Add("ppc64el", "ppc64le")
+	aliases.Add("x64", "x86_64")
+	aliases.Add("ppc64le", "powerpc64le")
+
+	if !aliases.Equal("x64", "amd64") || !aliases.Equal("ppc64el", "powerpc64le") {
+		t.Error("added aliases should join the existing group")
+	}
+
+	for arch, canonical := range map[string]string{"x64": "amd64", "x86_64": "amd64", "amd64": "amd64", "powerpc64le": "ppc64el"} {
+		if c := aliases.Canonical(arch); c != canonical {
+			t.Errorf("%s: expected canonical arch %s got %s", arch, canonical, c)
+		}
+	}
+
+	if ArchEqual("x64", "amd64") || NewArchAliases().Equal("x64", "amd64") {
+		t.Error("added aliases should not change the built in aliases")
+	}
+}
+
+func TestArchAliasParse(t *testing.T) {
+	srcinfo, err := Parse(`pkgbase = foo
+	pkgver = 1
+	arch = amd64
+	arch = arm64
+	depends = a
+	depends_x86_64 = b
+	depends_jammy_aarch64 = c
+pkgname = foo
+	depends_amd64 = d`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ArchDistroString{{"x86_64", "", "b"}, {"aarch64", "jammy", "c"}}
+	if !reflect.DeepEqual(srcinfo.Depends[1:], expected) {
+		t.Errorf("expected %#v got %#v", expected, srcinfo.Depends[1:])
+	}
+
+	pkg, _ := srcinfo.SplitPackage("foo")
+	expected = []ArchDistroString{{"amd64", "", "d"}, {"", "", "a"}, {"aarch64", "jammy", "c"}}
+	if !reflect.DeepEqual(pkg.Depends, expected) {
+		t.Errorf("aliases should override each other, expected %#v got %#v", expected, pkg.Depends)
+	}
+
+	resolved := srcinfo.Resolve(Target{"x86_64", "ubuntu", "jammy"})
+	if len(resolved) != 1 || !reflect.DeepEqual(resolved[0].Depends, []string{"a", "d"}) {
+		t.Errorf("unexpected resolved packages: %#v", resolved)
+	}
+
+	resolved = srcinfo.Resolve(Target{"aarch64", "ubuntu", "jammy"})
+	if len(resolved) != 1 || !reflect.DeepEqual(resolved[0].Depends, []string{"a", "c"}) {
+		t.Errorf("unexpected resolved packages: %#v", resolved)
+	}
+
+	_, err = Parse("pkgbase = foo\n\tpkgver = 1\n\tarch = all\n\tdepends_all = a\npkgname = foo")
+	if !errors.Is(err, ErrInvalidArch) {
+		t.Errorf("all should be rejected as a suffix like any, got %v", err)
+	}
+
+	srcinfo, err = Parse("pkgbase = foo\n\tpkgver = 1\n\tarch = all\npkgname = foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resolved := srcinfo.Resolve(Target{Arch: "amd64"}); len(resolved) != 1 {
+		t.Errorf("arch all should support every target, got %#v", resolved)
+	}
+
+	_, err = ParseWithOptions("pkgbase = foo\n\tpkgver = 1\n\tarch = amd64\n\tdepends_jammy_x86_64 = a\npkgname = foo",
+		ParseOptions{Registry: DefaultRegistry()})
+	if err != nil {
+		t.Errorf("aliases should be accepted with a registry: %s", err)
+	}
+}
+
+func TestArchAliasOption(t *testing.T) {
+	data := `pkgbase = foo
+	pkgver = 1
+	arch = amd64
+	depends = a
+	depends_x64 = b
+	depends_mydistro = d
+pkgname = foo
+	depends_x86_64 = c`
+
+	aliases := NewArchAliases()
+	aliases.Add("x64", "x86_64")
+
+	srcinfo, err := ParseWithOptions(data, ParseOptions{ArchAliases: aliases})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if srcinfo.Depends[1] != (ArchDistroString{"x64", "", "b"}) {
+		t.Errorf("x64 should be read as an arch, got %#v", srcinfo.Depends[1])
+	}
+
+	pkg, _ := srcinfo.SplitPackage("foo")
+	expected := []ArchDistroString{{"x86_64", "", "c"}, {"", "", "a"}, {"", "mydistro", "d"}}
+	if !reflect.DeepEqual(pkg.Depends, expected) {
+		t.Errorf("added aliases should override each other, expected %#v got %#v", expected, pkg.Depends)
+	}
+
+	resolved := srcinfo.Clone().Resolve(Target{Arch: "x64"})
+	if len(resolved) != 1 || !reflect.DeepEqual(resolved[0].Depends, []string{"a", "c"}) {
+		t.Errorf("unexpected resolved packages: %#v", resolved)
+	}
+
+	if values := (Target{Arch: "x64"}).ValuesWith(aliases, pkg.Depends); !reflect.DeepEqual(values, []string{"a", "c"}) {
+		t.Errorf("ValuesWith should use the given aliases, got %v", values)
+	}
+
+	if values := (Target{Arch: "x64"}).Values(pkg.Depends); !reflect.DeepEqual(values, []string{"a"}) {
+		t.Errorf("Values should use the built in aliases, got %v", values)
+	}
+
+	// the srcinfo keeps its own copy of the aliases
+	aliases.Add("amd64", "foo64")
+	if resolved := srcinfo.Resolve(Target{Arch: "foo64"}); len(resolved) != 0 {
+		t.Errorf("aliases added after parsing should not be used: %#v", resolved)
+	}
+}
@@ -0,0 +1,207 @@
+package srcinfo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors that may be wrapped by a ChecksumError. Use errors.Is to check for
+// them.
+var (
+	ErrChecksumCount   = errors.New("checksum count does not match source count")
+	ErrMissingChecksum = errors.New("missing checksums")
+	ErrInvalidChecksum = errors.New("invalid checksum")
+)
+
+// ChecksumAlgos are the checksum algorithms that may be used, named as the
+// checksum fields without the "sums" suffix.
+var ChecksumAlgos = []string{"md5", "sha1", "sha224", "sha256", "sha384", "sha512", "b2"}
+
+// checksumLengths are the number of hex digits in a sum of each algorithm.
+var checksumLengths = map[string]int{
+	"md5":    32,
+	"sha1":   40,
+	"sha224": 56,
+	"sha256": 64,
+	"sha384": 96,
+	"sha512": 128,
+	"b2":     128,
+}
+
+// checksums returns the checksum field of an algorithm.
+func (si *Srcinfo) checksums(algo string) []ArchDistroString {
+	switch algo {
+	case "md5":
+		return si.MD5Sums
+	case "sha1":
+		return si.SHA1Sums
+	case "sha224":
+		return si.SHA224Sums
+	case "sha256":
+		return si.SHA256Sums
+	case "sha384":
+		return si.SHA384Sums
+	case "sha512":
+		return si.SHA512Sums
+	case "b2":
+		return si.B2Sums
+	}
+
+	return nil
+}
+
+// SourceChecksum is a source paired with its checksums.
+type SourceChecksum struct {
+	Source Source
+	Sums   map[string]string // Algorithm to sum, SKIP values are left out
+	Skip   bool              // Every sum of the source is SKIP
+}
+
+// ChecksumError is an error found while pairing sources with checksums.
+type ChecksumError struct {
+	Err    error  // The kind of error, one of the ErrChecksum variables
+	Algo   string // The algorithm at fault, empty if none is used at all
+	Arch   string
+	Distro string
+	Index  int    // Index of the sum at fault, -1 if not about a single sum
+	Sum    string // The sum at fault, if any
+
+	Sources int // Number of sources for the arch and distro
+	Sums    int // Number of sums for the arch, distro and algorithm
+}
+
+// Error returns an error string describing the error.
+func (ce *ChecksumError) Error() string {
+	key := ce.Algo + "sums"
+	if ce.Algo == "" {
+		key = "checksums"
+	}
+
+	for _, suffix := range []string{ce.Distro, ce.Arch} {
+		if suffix != "" {
+			key += "_" + suffix
+		}
+	}
+
+	switch ce.Err {
+	case ErrChecksumCount:
+		return fmt.Sprintf("%s has %d sums but there are %d sources", key, ce.Sums, ce.Sources)
+	case ErrMissingChecksum:
+		return fmt.Sprintf("%s is missing for %d sources", key, ce.Sources)
+	default:
+		return fmt.Sprintf("%s sum %d \"%s\" is not a valid %s sum", key, ce.Index+1, ce.Sum, ce.Algo)
+	}
+}
+
+// Unwrap returns the kind of error, allowing errors.Is to be used with the
+// ErrChecksum variables.
+func (ce *ChecksumError) Unwrap() error {
+	return ce.Err
+}
+
+// Checksums pairs the sources for an arch and distro with their checksums.
+// Sources and sums are matched by position among the values with the same arch
+// and distro, so Checksums("x86_64", "") pairs source_x86_64 with
+// sha256sums_x86_64 and so on. Arch aliases are treated as the same arch, so
+// source_x86_64 may also be paired with sha256sums_amd64. Use empty strings
+// for the unsuffixed fields.
+//
+// Every algorithm used anywhere in the srcinfo must give one sum per source.
+// Each problem is returned as a *ChecksumError joined with errors.Join,
+// alongside the pairs that could still be made. Sums that are not SKIP must be
+// hex of the right length for their algorithm.
+func (si *Srcinfo) Checksums(arch, distro string) ([]SourceChecksum, error) {
+	var values []ArchDistroString
+	for _, source := range si.Source {
+		if si.aliases.Equal(source.Arch, arch) && source.Distro == distro {
+			values = append(values, source)
+		}
+	}
+
+	sources, err := ParseSources(values)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]SourceChecksum, 0, len(sources))
+	for _, source := range sources {
+		pairs = append(pairs, SourceChecksum{Source: source, Sums: make(map[string]string)})
+	}
+
+	var errs []error
+	used := false
+
+	for _, algo := range ChecksumAlgos {
+		all := si.checksums(algo)
+		if len(all) == 0 {
+			continue
+		}
+
+		used = true
+
+		var sums []string
+		for _, sum := range all {
+			if si.aliases.Equal(sum.Arch, arch) && sum.Distro == distro && sum.Value != EmptyOverride {
+				sums = append(sums, sum.Value)
+			}
+		}
+
+		switch {
+		case len(sums) == 0 && len(sources) > 0:
+			errs = append(errs, &ChecksumError{Err: ErrMissingChecksum, Algo: algo, Arch: arch, Distro: distro,
+				Index: -1, Sources: len(sources)})
+			continue
+		case len(sums) != len(sources):
+			errs = append(errs, &ChecksumError{Err: ErrChecksumCount, Algo: algo, Arch: arch, Distro: distro,
+				Index: -1, Sources: len(sources), Sums: len(sums)})
+		}
+
+		for n, sum := range sums {
+			if sum != "SKIP" && !validChecksum(algo, sum) {
+				errs = append(errs, &ChecksumError{Err: ErrInvalidChecksum, Algo: algo, Arch: arch, Distro: distro,
+					Index: n, Sum: sum, Sources: len(sources), Sums: len(sums)})
+				continue
+			}
+
+			if n >= len(pairs) {
+				continue
+			}
+
+			if sum == "SKIP" {
+				pairs[n].Skip = true
+			} else {
+				pairs[n].Sums[algo] = sum
+			}
+		}
+	}
+
+	if !used && len(sources) > 0 {
+		errs = append(errs, &ChecksumError{Err: ErrMissingChecksum, Arch: arch, Distro: distro,
+			Index: -1, Sources: len(sources)})
+	}
+
+	// a source is only skipped if no algorithm gives it a sum
+	for n := range pairs {
+		if len(pairs[n].Sums) != 0 {
+			pairs[n].Skip = false
+		}
+	}
+
+	return pairs, errors.Join(errs...)
+}
+
+// validChecksum checks that sum is lower or upper case hex of the right length
+// for algo.
+func validChecksum(algo, sum string) bool {
+	if len(sum) != checksumLengths[algo] {
+		return false
+	}
+
+	for _, c := range []byte(sum) {
+		if !isDigit(c) && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,152 @@
+package srcinfo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChecksums(t *testing.T) {
+	srcinfo, err := Parse(srcinfoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := srcinfo.Checksums("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pairs) != len(srcinfo.Source) {
+		t.Fatalf("expected %d pairs got %d", len(srcinfo.Source), len(pairs))
+	}
+
+	if pairs[0].Sums["sha256"] != "63f6dc8e3c9f3a0273d5d6f4dca38a2413ca3a5f689329d05b750e4c87bb21b9" || pairs[0].Skip {
+		t.Errorf("unexpected first pair: %#v", pairs[0])
+	}
+
+	if !pairs[1].Skip || len(pairs[1].Sums) != 0 || pairs[1].Source.Name() != "linux-4.16.tar.sign" {
+		t.Errorf("unexpected second pair: %#v", pairs[1])
+	}
+}
+
+func TestChecksumsArch(t *testing.T) {
+	srcinfo, err := ParseFile("testdata/srcinfos/example_SRCINFO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := srcinfo.Checksums("x86_64", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pairs) != 1 || pairs[0].Sums["md5"] != "16d3067ebb3938dba46429a4d9f6178f" || pairs[0].Source.Arch != "x86_64" {
+		t.Errorf("unexpected pairs: %#v", pairs)
+	}
+}
+
+func TestChecksumsArchAlias(t *testing.T) {
+	srcinfo, err := Parse(`pkgbase = foo
+	pkgver = 1
+	arch = amd64
+	source_x86_64 = foo.tar.gz
+	sha256sums_amd64 = SKIP
+pkgname = foo`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, arch := range []string{"amd64", "x86_64"} {
+		pairs, err := srcinfo.Checksums(arch, "")
+		if err != nil {
+			t.Errorf("%s: %s", arch, err)
+		} else if len(pairs) != 1 || !pairs[0].Skip || pairs[0].Source.Name() != "foo.tar.gz" {
+			t.Errorf("%s: unexpected pairs: %#v", arch, pairs)
+		}
+	}
+}
+
+// checksumErrors returns the ChecksumErrors joined in err.
+func checksumErrors(err error) []*ChecksumError {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+
+	var errs []*ChecksumError
+	for _, err := range joined.Unwrap() {
+		var ce *ChecksumError
+		if errors.As(err, &ce) {
+			errs = append(errs, ce)
+		}
+	}
+
+	return errs
+}
+
+func TestChecksumErrors(t *testing.T) {
+	const md5 = "cc8dcd66b189245e39296b1382d0dfcc"
+	const sha1 = "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+
+	srcinfo := &Srcinfo{}
+	srcinfo.Source = []ArchDistroString{
+		{"", "", "a.tar.gz"},
+		{"", "", "b.tar.gz"},
+		{"x86_64", "jammy", "c.tar.gz"},
+	}
+	srcinfo.MD5Sums = []ArchDistroString{
+		{"", "", md5},
+		{"", "", "not-hex"},
+		{"", "", md5},
+	}
+	srcinfo.SHA1Sums = []ArchDistroString{
+		{"", "", sha1},
+		{"", "", "SKIP"},
+	}
+
+	pairs, err := srcinfo.Checksums("", "")
+
+	errs := checksumErrors(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors got %v", err)
+	}
+
+	if !errors.Is(errs[0], ErrChecksumCount) || errs[0].Algo != "md5" || errs[0].Sums != 3 || errs[0].Sources != 2 {
+		t.Errorf("unexpected error: %#v", errs[0])
+	}
+
+	if !errors.Is(errs[1], ErrInvalidChecksum) || errs[1].Index != 1 || errs[1].Sum != "not-hex" {
+		t.Errorf("unexpected error: %#v", errs[1])
+	}
+
+	if errs[1].Error() != "md5sums sum 2 \"not-hex\" is not a valid md5 sum" {
+		t.Errorf("unexpected error string: %s", errs[1].Error())
+	}
+
+	if len(pairs) != 2 || pairs[0].Sums["md5"] != md5 || pairs[0].Sums["sha1"] != sha1 || !pairs[1].Skip {
+		t.Errorf("unexpected pairs: %#v", pairs)
+	}
+
+	_, err = srcinfo.Checksums("x86_64", "jammy")
+	if errs = checksumErrors(err); len(errs) != 2 {
+		t.Fatalf("expected 2 errors got %v", err)
+	}
+
+	for _, err := range errs {
+		if !errors.Is(err, ErrMissingChecksum) {
+			t.Errorf("expected missing checksum got %#v", err)
+		}
+	}
+
+	if errs[0].Error() != "md5sums_jammy_x86_64 is missing for 1 sources" {
+		t.Errorf("unexpected error string: %s", errs[0].Error())
+	}
+
+	srcinfo.MD5Sums = nil
+	srcinfo.SHA1Sums = nil
+
+	_, err = srcinfo.Checksums("", "")
+	if !errors.Is(err, ErrMissingChecksum) {
+		t.Errorf("expected missing checksum got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package srcinfo
+
+import (
+	"slices"
+)
+
+// Clone returns a deep copy of the package that shares no slices with it.
+func (pkg *Package) Clone() *Package {
+	clone := &Package{}
+	*clone = *pkg
+
+	for _, field := range builtinFields {
+		if field.Scope != ScopePackage {
+			continue
+		}
+
+		switch {
+		case field.strs != nil:
+			*field.strs(nil, clone) = slices.Clone(*field.strs(nil, pkg))
+		case field.ads != nil:
+			*field.ads(nil, clone) = slices.Clone(*field.ads(nil, pkg))
+		}
+	}
+
+	clone.Extra = slices.Clone(pkg.Extra)
+	return clone
+}
+
+// Clone returns a deep copy of the srcinfo that shares no slices with it.
+func (si *Srcinfo) Clone() *Srcinfo {
+	clone := &Srcinfo{registry: si.registry, aliases: si.aliases}
+	clone.PackageBase = si.PackageBase
+	clone.Package = *si.Package.Clone()
+
+	for _, field := range builtinFields {
+		if field.Scope != ScopeBase {
+			continue
+		}
+
+		switch {
+		case field.strs != nil:
+			*field.strs(clone, nil) = slices.Clone(*field.strs(si, nil))
+		case field.ads != nil:
+			*field.ads(clone, nil) = slices.Clone(*field.ads(si, nil))
+		}
+	}
+
+	if si.Packages != nil {
+		clone.Packages = make([]Package, 0, len(si.Packages))
+		for n := range si.Packages {
+			clone.Packages = append(clone.Packages, *si.Packages[n].Clone())
+		}
+	}
+
+	return clone
+}
+
+// View is a read only view of a Srcinfo. The View keeps its own copy of the
+// srcinfo and every method returns values that share nothing with it, so a
+// View may be used by many goroutines at once and callers may freely modify
+// what it returns.
+type View struct {
+	si *Srcinfo
+}
+
+// NewView returns a View of a copy of si. Later changes to si do not affect
+// the View.
+func NewView(si *Srcinfo) *View {
+	return &View{si.Clone()}
+}
+
+// Srcinfo returns a copy of the srcinfo.
+func (v *View) Srcinfo() *Srcinfo {
+	return v.si.Clone()
+}
+
+// Pkgbase returns the pkgbase of the srcinfo.
+func (v *View) Pkgbase() string {
+	return v.si.Pkgbase
+}
+
+// Version returns the version of the srcinfo, see Srcinfo.Version.
+func (v *View) Version() string {
+	return v.si.Version()
+}
+
+// Pkgnames returns the name of every package of the srcinfo.
+func (v *View) Pkgnames() []string {
+	pkgnames := make([]string, 0, len(v.si.Packages))
+
+	for _, pkg := range v.si.Packages {
+		pkgnames = append(pkgnames, pkg.Pkgname)
+	}
+
+	return pkgnames
+}
+
+// SplitPackage returns a copy of the merged package, see
+// Srcinfo.SplitPackage.
+func (v *View) SplitPackage(pkgname string) (*Package, error) {
+	pkg, err := v.si.SplitPackage(pkgname)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.Clone(), nil
+}
+
+// SplitPackages returns a copy of every merged package, see
+// Srcinfo.SplitPackages.
+func (v *View) SplitPackages() []*Package {
+	pkgs := v.si.SplitPackages()
+
+	for n, pkg := range pkgs {
+		pkgs[n] = pkg.Clone()
+	}
+
+	return pkgs
+}
+
+// Resolve returns every package as it applies to the target, see
+// Srcinfo.Resolve.
+func (v *View) Resolve(t Target) []ResolvedPackage {
+	return v.si.Resolve(t)
+}
+
+// CompatibleWith checks the srcinfo against the target, see
+// Srcinfo.CompatibleWith.
+func (v *View) CompatibleWith(t Target) Verdict {
+	return v.si.CompatibleWith(t)
+}
+
+// String generates the srcinfo data of the srcinfo, see Srcinfo.String.
+func (v *View) String() string {
+	return v.si.String()
+}
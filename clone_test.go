@@ -0,0 +1,121 @@
+package srcinfo
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	srcinfo, err := Parse(srcinfoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := srcinfo.Clone()
+	if !reflect.DeepEqual(clone, srcinfo) {
+		t.Fatalf("clone does not match:\n\n%#v\n\n%#v", srcinfo, clone)
+	}
+
+	clone.Arch[0] = "aarch64"
+	clone.Source[0].Value = "changed"
+	clone.Depends = append(clone.Depends[:0], ArchDistroString{Value: "changed"})
+	clone.Extra[0].Value = "changed"
+	clone.Packages[0].Extra[0].Value = "changed"
+	clone.Packages[1].Pkgdesc = "changed"
+
+	expected, _ := Parse(srcinfoData)
+	if !reflect.DeepEqual(srcinfo, expected) {
+		t.Errorf("modifying the clone changed the original")
+	}
+
+	pkg, err := srcinfo.SplitPackage("linux-ck")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgClone := pkg.Clone()
+	pkgClone.Arch[0] = "changed"
+	pkgClone.Provides[0].Value = "changed"
+
+	if srcinfo.Arch[0] != "x86_64" {
+		t.Errorf("modifying a cloned package changed the srcinfo")
+	}
+}
+
+func TestView(t *testing.T) {
+	srcinfo, err := Parse(srcinfoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewView(srcinfo)
+	srcinfo.Arch[0] = "changed"
+
+	expected, _ := Parse(srcinfoData)
+	if !reflect.DeepEqual(view.Srcinfo(), expected) {
+		t.Errorf("changing the srcinfo changed the view")
+	}
+
+	if view.Pkgbase() != "linux-ck" || view.Version() != expected.Version() {
+		t.Errorf("unexpected pkgbase or version: %s %s", view.Pkgbase(), view.Version())
+	}
+
+	if !reflect.DeepEqual(view.Pkgnames(), []string{"linux-ck", "linux-ck-headers"}) {
+		t.Errorf("unexpected pkgnames: %v", view.Pkgnames())
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for _, pkg := range view.SplitPackages() {
+				pkg.Arch[0] = "changed"
+				pkg.Depends = append(pkg.Depends, ArchDistroString{Value: "changed"})
+			}
+
+			pkg, _ := view.SplitPackage("linux-ck")
+			pkg.Extra[0].Value = "changed"
+
+			for _, pkg := range view.Resolve(Target{Arch: "x86_64"}) {
+				pkg.Arch[0] = "changed"
+				pkg.Source[0] = "changed"
+			}
+
+			view.Srcinfo().Source[0].Value = "changed"
+			_ = view.String()
+			_ = view.CompatibleWith(Target{Distro: "ubuntu"})
+		}()
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(view.Srcinfo(), expected) {
+		t.Errorf("modifying returned values changed the view")
+	}
+}
+
+func TestViewRegistry(t *testing.T) {
+	reg := DefaultRegistry()
+
+	srcinfo, err := ParseWithOptions(`pkgbase = foo
+	pkgver = 1
+	arch = amd64
+	compatible = *:cosmic
+pkgname = foo`, ParseOptions{Registry: reg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewView(srcinfo)
+
+	// the srcinfo keeps its own copy of the registry
+	reg.AddDistro("pop", "cosmic")
+
+	for _, targets := range [][]TargetPackages{srcinfo.Targets(), view.Srcinfo().Targets()} {
+		if len(targets) != 1 || targets[0].Target != (Target{"amd64", "", "cosmic"}) {
+			t.Errorf("changing the registry after parsing changed the targets: %#v", targets)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package srcinfo
+
+import (
+	"strings"
+)
+
+// Verdict is the result of checking a srcinfo against a Target, along with the
+// rule that decided it.
+type Verdict struct {
+	Compatible bool
+	Field      string // "compatible" or "incompatible", empty if neither is set
+	Rule       string // The entry that matched, empty if none did
+}
+
+// matchRule reports whether a compatible or incompatible entry matches the
+// target. Entries are in the form distro:codename where either part may be *
+// to match anything. An entry without a colon matches any codename of the
+// distro.
+func (t Target) matchRule(rule string) bool {
+	distro, codename, ok := strings.Cut(rule, ":")
+	if !ok {
+		codename = "*"
+	}
+
+	return (distro == "*" || distro == t.Distro) && (codename == "*" || codename == t.Codename)
+}
+
+// CompatibleWith checks the compatible and incompatible fields against the
+// target using the same precedence as pacstall:
+//
+//	an incompatible entry matching the target makes it incompatible
+//	otherwise, if compatible is set, an entry must match the target
+//	otherwise the target is compatible
+//
+// For example compatible = ubuntu:* allows every Ubuntu release, and
+// incompatible = ubuntu:focal then excludes focal.
+func (si *Srcinfo) CompatibleWith(t Target) Verdict {
+	for _, rule := range si.Incompatible {
+		if rule != EmptyOverride && t.matchRule(rule) {
+			return Verdict{false, "incompatible", rule}
+		}
+	}
+
+	compatible := false
+	for _, rule := range si.Compatible {
+		if rule == EmptyOverride {
+			continue
+		}
+
+		if t.matchRule(rule) {
+			return Verdict{true, "compatible", rule}
+		}
+
+		compatible = true
+	}
+
+	if compatible {
+		return Verdict{false, "compatible", ""}
+	}
+
+	return Verdict{Compatible: true}
+}
@@ -0,0 +1,48 @@
+package srcinfo
+
+import (
+	"testing"
+)
+
+func TestCompatibleWith(t *testing.T) {
+	srcinfo, err := Parse(`pkgbase = foo
+	pkgver = 1
+	compatible = ubuntu:*
+	compatible = debian:bookworm
+	compatible = *:sid
+	incompatible = ubuntu:focal
+	incompatible = linuxmint
+pkgname = foo`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target  Target
+		verdict Verdict
+	}{
+		{Target{Distro: "ubuntu", Codename: "jammy"}, Verdict{true, "compatible", "ubuntu:*"}},
+		{Target{Distro: "ubuntu", Codename: "focal"}, Verdict{false, "incompatible", "ubuntu:focal"}},
+		{Target{Distro: "debian", Codename: "bookworm"}, Verdict{true, "compatible", "debian:bookworm"}},
+		{Target{Distro: "debian", Codename: "bullseye"}, Verdict{false, "compatible", ""}},
+		{Target{Distro: "debian", Codename: "sid"}, Verdict{true, "compatible", "*:sid"}},
+		{Target{Distro: "linuxmint", Codename: "sid"}, Verdict{false, "incompatible", "linuxmint"}},
+		{Target{Distro: "fedora", Codename: "40"}, Verdict{false, "compatible", ""}},
+	}
+
+	for _, test := range tests {
+		if verdict := srcinfo.CompatibleWith(test.target); verdict != test.verdict {
+			t.Errorf("%#v: expected %#v got %#v", test.target, test.verdict, verdict)
+		}
+	}
+
+	srcinfo.Compatible = nil
+	if verdict := srcinfo.CompatibleWith(Target{Distro: "fedora", Codename: "40"}); verdict != (Verdict{Compatible: true}) {
+		t.Errorf("expected compatible with no rule got %#v", verdict)
+	}
+
+	srcinfo.Incompatible = []string{"*:*"}
+	if verdict := srcinfo.CompatibleWith(Target{Distro: "fedora", Codename: "40"}); verdict.Compatible {
+		t.Errorf("*:* should match everything got %#v", verdict)
+	}
+}
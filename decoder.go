@@ -0,0 +1,70 @@
+package srcinfo
+
+import (
+	"bufio"
+	"io"
+)
+
+// Decoder reads and parses a srcinfo from an input stream.
+//
+// The input is consumed one line at a time, so only the current line and the
+// Srcinfo being built are held in memory regardless of the size of the input.
+type Decoder struct {
+	r    *bufio.Reader
+	opts ParseOptions
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// SetOptions sets the options used by subsequent calls to Decode.
+func (dec *Decoder) SetOptions(opts ParseOptions) {
+	dec.opts = opts
+}
+
+// Decode reads the srcinfo from the input until EOF and returns the parsed
+// Srcinfo. Parsing follows the same rules as Parse and errors are reported
+// using the same line numbers.
+//
+// If ContinueOnError is set then parse errors do not stop decoding, instead
+// the partial Srcinfo is returned along with an ErrorList.
+func (dec *Decoder) Decode() (*Srcinfo, error) {
+	psr := newParser(dec.opts)
+	var errs ErrorList
+
+	n := 1
+	for ; ; n++ {
+		line, err := dec.r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if perr := psr.parseLine(n, line); perr != nil {
+			if !dec.opts.ContinueOnError {
+				return nil, perr
+			}
+
+			errs = append(errs, perr)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if ferrs := psr.finish(n); len(ferrs) != 0 {
+		if !dec.opts.ContinueOnError {
+			return nil, ferrs[0]
+		}
+
+		errs = append(errs, ferrs...)
+	}
+
+	if len(errs) != 0 {
+		return psr.srcinfo, errs
+	}
+
+	return psr.srcinfo, nil
+}
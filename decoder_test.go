@@ -0,0 +1,86 @@
+package srcinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// oneByteReader returns at most one byte per Read call to make sure the
+// Decoder does not depend on the size of reads.
+type oneByteReader struct {
+	data string
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, os.ErrClosed
+	}
+
+	p[0] = r.data[0]
+	r.data = r.data[1:]
+	return 1, nil
+}
+
+func TestDecoderMatchesParse(t *testing.T) {
+	for _, name := range goodSrcinfos {
+		path := filepath.Join(goodSrcinfoDir, name)
+		file, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("Unable to read file: %s: %s", path, err.Error())
+			continue
+		}
+
+		expected, err := Parse(string(file))
+		if err != nil {
+			t.Errorf("Error parsing %s: %s", name, err)
+			continue
+		}
+
+		srcinfo, err := NewDecoder(strings.NewReader(string(file))).Decode()
+		if err != nil {
+			t.Errorf("Error decoding %s: %s", name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(srcinfo, expected) {
+			t.Errorf("srcinfos do not match for %s:\n\n%#v\n\n%#v", name, expected, srcinfo)
+		}
+	}
+}
+
+func TestDecoderLineError(t *testing.T) {
+	data := "pkgbase = foo\n\tpkgver = 1\n\n# comment\npkgbase = bar\n"
+
+	_, err := NewDecoder(strings.NewReader(data)).Decode()
+	lineError, ok := err.(*LineError)
+	if !ok {
+		t.Fatalf("expected a LineError but got: %v", err)
+	}
+
+	if lineError.LineNumber != 5 {
+		t.Errorf("Line number should be %d but was %d", 5, lineError.LineNumber)
+	}
+}
+
+func TestDecoderNoTrailingNewline(t *testing.T) {
+	data := "pkgbase = foo\n\tpkgver = 1\npkgname = bar"
+
+	srcinfo, err := NewDecoder(strings.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Error decoding data: %s", err)
+	}
+
+	if len(srcinfo.Packages) != 1 || srcinfo.Packages[0].Pkgname != "bar" {
+		t.Errorf("last line without newline was not parsed: %#v", srcinfo.Packages)
+	}
+}
+
+func TestDecoderReadError(t *testing.T) {
+	_, err := NewDecoder(&oneByteReader{"pkgbase = foo\n"}).Decode()
+	if err != os.ErrClosed {
+		t.Errorf("expected read error to be returned but got: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Package dep parses the dependency expressions used by relation fields of a
+// srcinfo such as depends, pacdeps, optdepends, conflicts and provides.
+//
+// Both the makepkg style "foo>=1.2" and the Debian style "foo (>= 1.2)" are
+// understood, as are alternatives "a | b", arch qualifiers "foo:any" and
+// optdepends descriptions "foo: reason". Relations are printed back in the
+// makepkg style.
+package dep
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pacstall/go-srcinfo"
+)
+
+// Op is a version comparison operator.
+type Op string
+
+// Version comparison operators. The Debian operators << and >> are parsed as
+// OpLess and OpGreater.
+const (
+	OpNone         Op = ""
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpEqual        Op = "="
+	OpGreaterEqual Op = ">="
+	OpGreater      Op = ">"
+)
+
+// ops lists each operator spelling, longest first so that prefixes do not
+// match early.
+var ops = [...]struct {
+	str string
+	op  Op
+}{
+	{"<<", OpLess},
+	{">>", OpGreater},
+	{"<=", OpLessEqual},
+	{">=", OpGreaterEqual},
+	{"=", OpEqual},
+	{"<", OpLess},
+	{">", OpGreater},
+}
+
+// Dep is a single package that satisfies a Relation.
+type Dep struct {
+	Name    string // Package name
+	Arch    string // Arch qualifier, for example "any" in foo:any
+	Op      Op     // Version operator, OpNone if unversioned
+	Version string // Version, empty if unversioned
+}
+
+// Relation is a dependency expression. It is satisfied by any one of its
+// alternatives.
+type Relation struct {
+	Alternatives []Dep
+	Description  string // Description of an optdepends entry
+}
+
+// String formats the Dep as name[:arch][op version].
+func (d Dep) String() string {
+	str := d.Name
+
+	if d.Arch != "" {
+		str += ":" + d.Arch
+	}
+
+	return str + string(d.Op) + d.Version
+}
+
+// String formats the Relation in its canonical form, alternatives are
+// separated by " | " and the description follows ": ".
+func (r Relation) String() string {
+	alternatives := make([]string, 0, len(r.Alternatives))
+
+	for _, d := range r.Alternatives {
+		alternatives = append(alternatives, d.String())
+	}
+
+	str := strings.Join(alternatives, " | ")
+	if r.Description != "" {
+		str += ": " + r.Description
+	}
+
+	return str
+}
+
+// Names returns the package name of each alternative.
+func (r Relation) Names() []string {
+	names := make([]string, 0, len(r.Alternatives))
+
+	for _, d := range r.Alternatives {
+		names = append(names, d.Name)
+	}
+
+	return names
+}
+
+// Parse parses a dependency expression.
+func Parse(str string) (Relation, error) {
+	var relation Relation
+	expr := str
+
+	// a colon followed by whitespace, or at the end, starts a description. A
+	// colon followed by anything else is an arch qualifier.
+	for n := 0; n < len(expr); n++ {
+		if expr[n] != ':' {
+			continue
+		}
+
+		if n+1 == len(expr) || expr[n+1] == ' ' || expr[n+1] == '\t' {
+			relation.Description = strings.TrimSpace(expr[n+1:])
+			expr = expr[:n]
+			break
+		}
+	}
+
+	for _, alternative := range strings.Split(expr, "|") {
+		d, err := parseDep(strings.TrimSpace(alternative))
+		if err != nil {
+			return Relation{}, fmt.Errorf("Invalid dependency \"%s\": %s", str, err.Error())
+		}
+
+		relation.Alternatives = append(relation.Alternatives, d)
+	}
+
+	return relation, nil
+}
+
+func parseDep(str string) (Dep, error) {
+	var d Dep
+	name, constraint := str, ""
+
+	if open := strings.IndexByte(str, '('); open != -1 {
+		if !strings.HasSuffix(str, ")") {
+			return d, fmt.Errorf("missing )")
+		}
+
+		name = strings.TrimSpace(str[:open])
+		constraint = strings.TrimSpace(str[open+1 : len(str)-1])
+		if constraint == "" {
+			return d, fmt.Errorf("empty version constraint")
+		}
+	} else if n := strings.IndexAny(str, "<>="); n != -1 {
+		name = strings.TrimSpace(str[:n])
+		constraint = str[n:]
+	}
+
+	if constraint != "" {
+		for _, op := range ops {
+			if version, ok := strings.CutPrefix(constraint, op.str); ok {
+				d.Op = op.op
+				d.Version = strings.TrimSpace(version)
+				break
+			}
+		}
+
+		if d.Op == OpNone {
+			return d, fmt.Errorf("unknown operator in \"%s\"", constraint)
+		}
+
+		if d.Version == "" {
+			return d, fmt.Errorf("missing version")
+		}
+
+		if strings.ContainsAny(d.Version, " \t<>=()|") {
+			return d, fmt.Errorf("invalid version \"%s\"", d.Version)
+		}
+	}
+
+	d.Name, d.Arch, _ = strings.Cut(name, ":")
+
+	if d.Name == "" {
+		return d, fmt.Errorf("missing package name")
+	}
+
+	if strings.ContainsAny(name, " \t()") {
+		return d, fmt.Errorf("invalid package name \"%s\"", name)
+	}
+
+	return d, nil
+}
+
+// ParseAll parses the value of each ArchDistroString. Empty overrides are
+// skipped. The returned slice is in the same order as values, minus any
+// skipped entries.
+func ParseAll(values []srcinfo.ArchDistroString) ([]Relation, error) {
+	relations := make([]Relation, 0, len(values))
+
+	for _, value := range values {
+		if value.Value == srcinfo.EmptyOverride {
+			continue
+		}
+
+		relation, err := Parse(value.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		relations = append(relations, relation)
+	}
+
+	return relations, nil
+}
@@ -0,0 +1,94 @@
+package dep
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pacstall/go-srcinfo"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		str       string
+		relation  Relation
+		canonical string
+	}{
+		{"foo", Relation{Alternatives: []Dep{{Name: "foo"}}}, "foo"},
+		{"foo>=1.2", Relation{Alternatives: []Dep{{"foo", "", OpGreaterEqual, "1.2"}}}, "foo>=1.2"},
+		{"foo (>= 1.2)", Relation{Alternatives: []Dep{{"foo", "", OpGreaterEqual, "1.2"}}}, "foo>=1.2"},
+		{"foo (<< 2:1.0-1)", Relation{Alternatives: []Dep{{"foo", "", OpLess, "2:1.0-1"}}}, "foo<2:1.0-1"},
+		{"foo>>1", Relation{Alternatives: []Dep{{"foo", "", OpGreater, "1"}}}, "foo>1"},
+		{"foo=1", Relation{Alternatives: []Dep{{"foo", "", OpEqual, "1"}}}, "foo=1"},
+		{"foo<1", Relation{Alternatives: []Dep{{"foo", "", OpLess, "1"}}}, "foo<1"},
+		{"foo<=1", Relation{Alternatives: []Dep{{"foo", "", OpLessEqual, "1"}}}, "foo<=1"},
+		{"foo:any", Relation{Alternatives: []Dep{{Name: "foo", Arch: "any"}}}, "foo:any"},
+		{"python3:any (>= 3.8)", Relation{Alternatives: []Dep{{"python3", "any", OpGreaterEqual, "3.8"}}}, "python3:any>=3.8"},
+		{"a | b>1|c", Relation{Alternatives: []Dep{{Name: "a"}, {"b", "", OpGreater, "1"}, {Name: "c"}}}, "a | b>1 | c"},
+		{"foo: for bar support", Relation{Alternatives: []Dep{{Name: "foo"}}, Description: "for bar support"}, "foo: for bar support"},
+		{"foo:any: a: b", Relation{Alternatives: []Dep{{Name: "foo", Arch: "any"}}, Description: "a: b"}, "foo:any: a: b"},
+		{"foo>=1:", Relation{Alternatives: []Dep{{"foo", "", OpGreaterEqual, "1"}}}, "foo>=1"},
+	}
+
+	for _, test := range tests {
+		relation, err := Parse(test.str)
+		if err != nil {
+			t.Errorf("%s: %s", test.str, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(relation, test.relation) {
+			t.Errorf("%s: expected %#v got %#v", test.str, test.relation, relation)
+		}
+
+		if relation.String() != test.canonical {
+			t.Errorf("%s: expected canonical form \"%s\" got \"%s\"", test.str, test.canonical, relation.String())
+		}
+
+		again, err := Parse(relation.String())
+		if err != nil || !reflect.DeepEqual(again, relation) {
+			t.Errorf("%s: canonical form does not round trip: %#v %v", test.str, again, err)
+		}
+	}
+}
+
+func TestParseBad(t *testing.T) {
+	bad := []string{
+		"",
+		"foo |",
+		">=1",
+		"foo>=",
+		"foo (>= 1",
+		"foo ()",
+		"foo (~ 1)",
+		"foo bar",
+		"foo>=1 2",
+	}
+
+	for _, str := range bad {
+		if _, err := Parse(str); err == nil {
+			t.Errorf("%s: should have errored", str)
+		}
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	values := []srcinfo.ArchDistroString{
+		{Value: "foo>=1"},
+		{Arch: "amd64", Value: srcinfo.EmptyOverride},
+		{Distro: "jammy", Value: "bar | baz"},
+	}
+
+	relations, err := ParseAll(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(relations) != 2 || !reflect.DeepEqual(relations[1].Names(), []string{"bar", "baz"}) {
+		t.Errorf("unexpected relations: %#v", relations)
+	}
+
+	_, err = ParseAll([]srcinfo.ArchDistroString{{Value: "foo bar"}})
+	if err == nil {
+		t.Errorf("invalid relation should have errored")
+	}
+}
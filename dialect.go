@@ -0,0 +1,169 @@
+package srcinfo
+
+import (
+	"sort"
+)
+
+// Dialect describes the rules of a flavour of srcinfo. The same parser is used
+// for every dialect, the dialect only decides which keys are known, which
+// fields are required and what values are implied when a field is missing.
+//
+// pkgbase and at least one pkgname are always required.
+type Dialect struct {
+	// Name is a human readable name for the dialect.
+	Name string
+
+	// Schema describes the keys that are known. Keys that are not known are
+	// stored in Package.Extra, or are an error when parsing strictly.
+	Schema *Schema
+
+	// Required lists the pkgbase fields that must be present.
+	Required []string
+
+	// Defaults are the values given to pkgbase fields that are not present.
+	Defaults map[string]string
+
+	// DistroSuffixes allows keys to have a distro suffix as well as an arch
+	// suffix. Without it everything after the first underscore of a key is
+	// taken as the arch.
+	DistroSuffixes bool
+
+	// Strict makes unknown keys an error, as with ParseOptions.Strict.
+	Strict bool
+}
+
+// PacstallDialect describes srcinfos generated by pacstall. pkgrel defaults to
+// 1, arch defaults to any and fields may be distro specific.
+var PacstallDialect = &Dialect{
+	Name:           "pacstall",
+	Schema:         pacstallSchema(),
+	Required:       []string{"pkgver"},
+	Defaults:       map[string]string{"pkgrel": "1", "arch": "any"},
+	DistroSuffixes: true,
+}
+
+// ArchDialect describes srcinfos generated by makepkg, as found on the AUR.
+// pkgver, pkgrel and arch are required and fields may only be arch specific.
+var ArchDialect = &Dialect{
+	Name:     "arch",
+	Schema:   archSchema(),
+	Required: []string{"pkgver", "pkgrel", "arch"},
+}
+
+func pacstallSchema() *Schema {
+	names := make([]string, 0, len(builtinFields))
+
+	for _, field := range builtinFields {
+		names = append(names, field.Name)
+	}
+
+	schema := NewSchema(names...)
+	schema.readOnly = true
+
+	return schema
+}
+
+func archSchema() *Schema {
+	schema := NewSchema(
+		"pkgdesc", "pkgver", "pkgrel", "epoch", "url", "arch", "license",
+		"depends", "checkdepends", "makedepends", "optdepends", "conflicts",
+		"provides", "replaces", "source", "noextract", "md5sums", "sha1sums",
+		"sha224sums", "sha256sums", "sha384sums", "sha512sums", "b2sums",
+		"backup",
+	)
+
+	// makepkg fields without a field of their own
+	schema.Register(Field{Name: "install", Scope: ScopePackage})
+	schema.Register(Field{Name: "changelog", Scope: ScopePackage})
+	schema.Register(Field{Name: "groups", Scope: ScopePackage, Multi: true})
+	schema.Register(Field{Name: "options", Scope: ScopePackage, Multi: true})
+	schema.Register(Field{Name: "validpgpkeys", Scope: ScopeBase, Multi: true})
+	schema.readOnly = true
+
+	return schema
+}
+
+// field returns the field for key, without any suffix, if it is known to the
+// dialect.
+func (d *Dialect) field(key string) (*Field, bool) {
+	schema := d.Schema
+	if schema == nil {
+		schema = PacstallDialect.Schema
+	}
+
+	field, ok := schema.index[key]
+	return field, ok
+}
+
+// IsKnown reports whether key, without any suffix, is known to the dialect.
+func (d *Dialect) IsKnown(key string) bool {
+	_, ok := d.field(key)
+	return ok
+}
+
+// dialect returns the dialect the parser is using.
+func (psr *parser) dialect() *Dialect {
+	if psr.opts.Dialect != nil {
+		return psr.opts.Dialect
+	}
+
+	return PacstallDialect
+}
+
+// strict reports whether unknown keys are an error.
+func (psr *parser) strict() bool {
+	return psr.opts.Strict || psr.dialect().Strict
+}
+
+// missingFieldErrors maps required fields to the error used when they are
+// missing.
+var missingFieldErrors = map[string]error{
+	"pkgver": ErrMissingPkgver,
+	"pkgrel": ErrMissingPkgrel,
+	"arch":   ErrMissingArch,
+}
+
+// checkRequired reports every required field that was not seen and then fills
+// in defaults for the fields that are still missing.
+func (psr *parser) checkRequired() ErrorList {
+	var errs ErrorList
+	dialect := psr.dialect()
+
+	for _, key := range dialect.Required {
+		if _, ok := psr.seenKeys[key]; ok {
+			continue
+		}
+
+		kind, ok := missingFieldErrors[key]
+		if !ok {
+			kind = ErrMissingField
+		}
+
+		err := keyErrorf(kind, key, "No %s field", key)
+		errs = append(errs, lineError(psr.pkgbaseLine, psr.pkgbaseLineText, err))
+	}
+
+	keys := make([]string, 0, len(dialect.Defaults))
+	for key := range dialect.Defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, ok := psr.seenKeys[key]; ok {
+			continue
+		}
+
+		// defaults belong to the pkgbase so pretend no pkgname has been seen
+		packages := psr.srcinfo.Packages
+		psr.srcinfo.Packages = nil
+		err := psr.setField(key, dialect.Defaults[key])
+		psr.srcinfo.Packages = packages
+
+		if err != nil {
+			errs = append(errs, lineError(psr.pkgbaseLine, psr.pkgbaseLineText, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,74 @@
+package srcinfo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestArchDialect(t *testing.T) {
+	data := `pkgbase = foo
+	pkgver = 1
+	pkgrel = 2
+	arch = x86_64
+	install = foo.install
+	gives = bar
+	depends_x86_64 = baz
+
+pkgname = foo
+`
+
+	srcinfo, err := ParseWithOptions(data, ParseOptions{Dialect: ArchDialect})
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	expected := []ExtraField{
+		{Key: "install", ArchDistroString: ArchDistroString{Value: "foo.install"}},
+		{Key: "gives", ArchDistroString: ArchDistroString{Value: "bar"}},
+	}
+	if !reflect.DeepEqual(srcinfo.Extra, expected) {
+		t.Errorf("extra fields do not match:\n\n%#v\n\n%#v", expected, srcinfo.Extra)
+	}
+
+	if len(srcinfo.Gives) != 0 {
+		t.Errorf("gives is not an arch key but was parsed: %#v", srcinfo.Gives)
+	}
+
+	if len(srcinfo.Depends) != 1 || srcinfo.Depends[0].Arch != "x86_64" {
+		t.Errorf("depends_x86_64 was not parsed: %#v", srcinfo.Depends)
+	}
+
+	strict := *ArchDialect
+	strict.Strict = true
+
+	_, err = ParseWithOptions(data, ParseOptions{Dialect: &strict})
+	var lineError *LineError
+	if !errors.As(err, &lineError) || !errors.Is(err, ErrUnknownKey) || lineError.Key != "gives" {
+		t.Errorf("error should be %v for gives but was %v", ErrUnknownKey, err)
+	}
+}
+
+func TestDialectDefaults(t *testing.T) {
+	data := "pkgbase = foo\n\tpkgver = 1\npkgname = foo\n"
+
+	srcinfo, err := ParseWithOptions(data, ParseOptions{Dialect: PacstallDialect})
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	if srcinfo.Pkgrel != "1" || !reflect.DeepEqual(srcinfo.Arch, []string{"any"}) {
+		t.Errorf("defaults were not applied: pkgrel=%s arch=%v", srcinfo.Pkgrel, srcinfo.Arch)
+	}
+
+	custom := &Dialect{
+		Name:     "custom",
+		Schema:   NewSchema("pkgver", "pkgdesc"),
+		Required: []string{"pkgver", "pkgdesc"},
+	}
+
+	_, err = ParseWithOptions(data, ParseOptions{Dialect: custom, ContinueOnError: true})
+	if !errors.Is(err, ErrMissingField) {
+		t.Errorf("error should be %v but was %v", ErrMissingField, err)
+	}
+}
@@ -0,0 +1,248 @@
+package srcinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeKind describes what a line in a Document contains.
+type NodeKind int
+
+const (
+	// BlankNode is an empty or whitespace only line.
+	BlankNode NodeKind = iota
+	// CommentNode is a line starting with #.
+	CommentNode
+	// FieldNode is a "key = value" line, including pkgbase and pkgname
+	// headers.
+	FieldNode
+)
+
+// Node is a single line of a Document.
+//
+// Key and Value may be modified directly. A node whose Key and Value are
+// unchanged is printed exactly as it was read, otherwise the line is rebuilt
+// using the original indentation.
+type Node struct {
+	Kind  NodeKind
+	Key   string // Full key including any arch or distro suffix
+	Value string
+
+	raw    string // The original line
+	indent string // Leading whitespace of the original line
+	key    string // Key as it was parsed
+	value  string // Value as it was parsed
+}
+
+// String returns the line the node represents, without a trailing newline.
+func (n *Node) String() string {
+	if n.Kind != FieldNode || (n.raw != "" && n.Key == n.key && n.Value == n.value) {
+		return n.raw
+	}
+
+	return n.indent + n.Key + " = " + n.Value
+}
+
+// Document is a lossless representation of a srcinfo file. Unlike Srcinfo it
+// keeps comments, blank lines, whitespace and the original order of every
+// field so that a file can be edited and written back with only the modified
+// lines changing.
+type Document struct {
+	Nodes []*Node
+}
+
+// ParseDocument parses a srcinfo in string form into a Document. Only the
+// syntax of each line is checked, use Srcinfo to fully parse the document.
+func ParseDocument(data string) (*Document, error) {
+	doc := &Document{}
+
+	for n, line := range strings.Split(data, "\n") {
+		node := &Node{raw: line}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			node.Kind = BlankNode
+		case strings.HasPrefix(trimmed, "#"):
+			node.Kind = CommentNode
+		default:
+			key, value, err := splitPair(trimmed)
+			if err != nil {
+				return nil, lineError(n+1, line, err)
+			}
+
+			node.Kind = FieldNode
+			node.Key, node.key = key, key
+			node.Value, node.value = value, value
+			node.indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		}
+
+		doc.Nodes = append(doc.Nodes, node)
+	}
+
+	return doc, nil
+}
+
+// NewDocument creates a Document from a Srcinfo. The document is laid out
+// the same way as Srcinfo.String.
+func NewDocument(si *Srcinfo) (*Document, error) {
+	return ParseDocument(si.String())
+}
+
+// String generates the srcinfo data of the Document. An unmodified Document
+// produces exactly the data it was parsed from.
+func (doc *Document) String() string {
+	lines := make([]string, 0, len(doc.Nodes))
+
+	for _, node := range doc.Nodes {
+		lines = append(lines, node.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Srcinfo parses the Document into a Srcinfo.
+func (doc *Document) Srcinfo() (*Srcinfo, error) {
+	return Parse(doc.String())
+}
+
+// section finds the range of nodes belonging to pkgname, or to the pkgbase if
+// pkgname is empty. The header node is included in the range.
+func (doc *Document) section(pkgname string) (int, int, error) {
+	start := -1
+
+	for n, node := range doc.Nodes {
+		if node.Kind != FieldNode || (node.Key != "pkgbase" && node.Key != "pkgname") {
+			continue
+		}
+
+		if start != -1 {
+			return start, n, nil
+		}
+
+		if (pkgname == "" && node.Key == "pkgbase") || (node.Key == "pkgname" && node.Value == pkgname) {
+			start = n
+		}
+	}
+
+	if start == -1 {
+		if pkgname == "" {
+			return 0, 0, fmt.Errorf("Document has no pkgbase")
+		}
+
+		return 0, 0, fmt.Errorf("Package \"%s\" is not part of the document", pkgname)
+	}
+
+	return start, len(doc.Nodes), nil
+}
+
+// Get returns every value of key in the section of pkgname, or in the
+// pkgbase section if pkgname is empty.
+func (doc *Document) Get(pkgname, key string) ([]string, error) {
+	start, end, err := doc.section(pkgname)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, node := range doc.Nodes[start+1 : end] {
+		if node.Kind == FieldNode && node.Key == key {
+			values = append(values, node.Value)
+		}
+	}
+
+	return values, nil
+}
+
+// Set sets key to value in the section of pkgname, or in the pkgbase section
+// if pkgname is empty. The first existing line for key is modified in place
+// and any further lines for key are removed. If key is not present it is
+// appended as with Append.
+func (doc *Document) Set(pkgname, key, value string) error {
+	start, end, err := doc.section(pkgname)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	nodes := doc.Nodes[:start+1]
+
+	for _, node := range doc.Nodes[start+1 : end] {
+		if node.Kind == FieldNode && node.Key == key {
+			if found {
+				continue
+			}
+
+			found = true
+			node.Value = value
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if !found {
+		return doc.Append(pkgname, key, value)
+	}
+
+	doc.Nodes = append(nodes, doc.Nodes[end:]...)
+	return nil
+}
+
+// Append adds a new line for key in the section of pkgname, or in the pkgbase
+// section if pkgname is empty. The line is inserted after the last existing
+// line for key, or after the last field of the section if key is not present.
+func (doc *Document) Append(pkgname, key, value string) error {
+	start, end, err := doc.section(pkgname)
+	if err != nil {
+		return err
+	}
+
+	after := start
+	for n := start + 1; n < end; n++ {
+		node := doc.Nodes[n]
+		if node.Kind != FieldNode {
+			continue
+		}
+
+		if node.Key == key || doc.Nodes[after].Key != key {
+			after = n
+		}
+	}
+
+	indent := "\t"
+	if after != start {
+		indent = doc.Nodes[after].indent
+	}
+
+	node := &Node{Kind: FieldNode, Key: key, Value: value, indent: indent}
+
+	doc.Nodes = append(doc.Nodes, nil)
+	copy(doc.Nodes[after+2:], doc.Nodes[after+1:])
+	doc.Nodes[after+1] = node
+	return nil
+}
+
+// Remove removes every line for key in the section of pkgname, or in the
+// pkgbase section if pkgname is empty. The number of removed lines is
+// returned.
+func (doc *Document) Remove(pkgname, key string) (int, error) {
+	start, end, err := doc.section(pkgname)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	nodes := doc.Nodes[:start+1]
+
+	for _, node := range doc.Nodes[start+1 : end] {
+		if node.Kind == FieldNode && node.Key == key {
+			removed++
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	doc.Nodes = append(nodes, doc.Nodes[end:]...)
+	return removed, nil
+}
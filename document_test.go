@@ -0,0 +1,170 @@
+package srcinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const documentData = `# Generated by hand
+pkgbase = foo
+	pkgver = 1.0
+	pkgrel = 1
+	arch = amd64
+	depends = bar
+	depends = baz
+
+# the main package
+pkgname = foo
+	pkgdesc = foo
+
+pkgname = foo-doc
+`
+
+func TestDocumentRoundTrip(t *testing.T) {
+	for _, name := range goodSrcinfos {
+		path := filepath.Join(goodSrcinfoDir, name)
+		file, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("Unable to read file: %s: %s", path, err.Error())
+			continue
+		}
+
+		doc, err := ParseDocument(string(file))
+		if err != nil {
+			t.Errorf("Error parsing %s: %s", name, err)
+			continue
+		}
+
+		if doc.String() != string(file) {
+			t.Errorf("%s did not round trip:\n%s", name, doc.String())
+		}
+	}
+}
+
+func TestDocumentSrcinfo(t *testing.T) {
+	doc, err := ParseDocument(srcinfoData)
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	srcinfo, err := doc.Srcinfo()
+	if err != nil {
+		t.Fatalf("Error converting document: %s", err)
+	}
+
+	expected, _ := Parse(srcinfoData)
+	if !reflect.DeepEqual(srcinfo, expected) {
+		t.Errorf("srcinfos do not match:\n\n%#v\n\n%#v", expected, srcinfo)
+	}
+
+	doc, err = NewDocument(expected)
+	if err != nil {
+		t.Fatalf("Error creating document: %s", err)
+	}
+
+	if doc.String() != expected.String() {
+		t.Errorf("document does not match srcinfo:\n\n%s\n\n%s", expected, doc)
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	doc, err := ParseDocument(documentData)
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	if err := doc.Set("", "pkgver", "2.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Set("", "depends", "qux"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Set("foo-doc", "pkgdesc", "docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.NewReplacer(
+		"pkgver = 1.0", "pkgver = 2.0",
+		"\tdepends = bar\n\tdepends = baz\n", "\tdepends = qux\n",
+		"pkgname = foo-doc\n", "pkgname = foo-doc\n\tpkgdesc = docs\n",
+	).Replace(documentData)
+
+	if doc.String() != expected {
+		t.Errorf("document does not match:\n\n%s\n\n%s", expected, doc)
+	}
+}
+
+func TestDocumentAppend(t *testing.T) {
+	doc, err := ParseDocument(documentData)
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	if err := doc.Append("", "depends_jammy", "quux"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Append("", "depends", "qux"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Append("foo", "depends", "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.NewReplacer(
+		"\tdepends = baz\n", "\tdepends = baz\n\tdepends = qux\n\tdepends_jammy = quux\n",
+		"\tpkgdesc = foo\n", "\tpkgdesc = foo\n\tdepends = bar\n",
+	).Replace(documentData)
+
+	if doc.String() != expected {
+		t.Errorf("document does not match:\n\n%s\n\n%s", expected, doc)
+	}
+
+	values, err := doc.Get("", "depends")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(values, []string{"bar", "baz", "qux"}) {
+		t.Errorf("unexpected depends: %v", values)
+	}
+}
+
+func TestDocumentRemove(t *testing.T) {
+	doc, err := ParseDocument(documentData)
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	removed, err := doc.Remove("", "depends")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if removed != 2 {
+		t.Errorf("expected 2 lines to be removed but %d were", removed)
+	}
+
+	expected := strings.Replace(documentData, "\tdepends = bar\n\tdepends = baz\n", "", 1)
+	if doc.String() != expected {
+		t.Errorf("document does not match:\n\n%s\n\n%s", expected, doc)
+	}
+
+	if _, err := doc.Remove("missing", "depends"); err == nil {
+		t.Errorf("removing from a missing package should error")
+	}
+}
+
+func TestDocumentBadLine(t *testing.T) {
+	_, err := ParseDocument("pkgbase = foo\n\tfoo\n")
+	lineError, ok := err.(*LineError)
+	if !ok || lineError.LineNumber != 2 {
+		t.Errorf("expected a LineError on line 2 but got: %v", err)
+	}
+}
@@ -20,10 +20,6 @@ var libcpp = &Srcinfo{
 			{Arch: "", Value: "https://releases.llvm.org/6.0.0/libcxxabi-6.0.0.src.tar.xz"},
 			{Arch: "", Value: "https://releases.llvm.org/6.0.0/libcxxabi-6.0.0.src.tar.xz.sig"},
 		},
-		ValidPGPKeys: []string{
-			"11E521D646982372EB577A1F8F0871F202119294",
-			"B6C8F98282B944E3B0D5C2530FC3042E345AD05D",
-		},
 		NoExtract: []string{
 			"llvm-6.0.0.src.tar.xz",
 			"llvm-6.0.0.src.tar.xz.sig",
@@ -52,7 +48,6 @@ var libcpp = &Srcinfo{
 			{Arch: "", Value: "python"},
 			{Arch: "", Value: "libunwind"},
 		},
-		CheckDepends: []ArchDistroString(nil),
 	},
 
 	Package: Package{
@@ -67,7 +62,6 @@ var libcpp = &Srcinfo{
 			"MIT",
 			"custom:University of Illinois/NCSA Open Source License",
 		},
-		Groups: []string(nil),
 		Depends: []ArchDistroString{
 			{Arch: "", Value: "gcc-libs"},
 		},
@@ -76,9 +70,10 @@ var libcpp = &Srcinfo{
 		Conflicts:  []ArchDistroString(nil),
 		Replaces:   []ArchDistroString(nil),
 		Backup:     []string(nil),
-		Options:    []string(nil),
-		Install:    "",
-		Changelog:  "",
+		Extra: []ExtraField{
+			{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "11E521D646982372EB577A1F8F0871F202119294"}},
+			{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "B6C8F98282B944E3B0D5C2530FC3042E345AD05D"}},
+		},
 	},
 	Packages: []Package{
 		{
@@ -87,7 +82,6 @@ var libcpp = &Srcinfo{
 			Arch:    []string(nil),
 			URL:     "",
 			License: []string(nil),
-			Groups:  []string(nil),
 			Depends: []ArchDistroString{
 				{Arch: "", Value: "libc++abi=6.0.0-1"},
 			},
@@ -96,9 +90,6 @@ var libcpp = &Srcinfo{
 			Conflicts:  []ArchDistroString(nil),
 			Replaces:   []ArchDistroString(nil),
 			Backup:     []string(nil),
-			Options:    []string(nil),
-			Install:    "",
-			Changelog:  "",
 		},
 		Package{
 			Pkgname:    "libc++abi",
@@ -106,16 +97,12 @@ var libcpp = &Srcinfo{
 			Arch:       []string(nil),
 			URL:        "",
 			License:    []string(nil),
-			Groups:     []string(nil),
 			Depends:    []ArchDistroString(nil),
 			OptDepends: []ArchDistroString(nil),
 			Provides:   []ArchDistroString(nil),
 			Conflicts:  []ArchDistroString(nil),
 			Replaces:   []ArchDistroString(nil),
 			Backup:     []string(nil),
-			Options:    []string(nil),
-			Install:    "",
-			Changelog:  "",
 		},
 		Package{
 			Pkgname: "libc++experimental",
@@ -123,7 +110,6 @@ var libcpp = &Srcinfo{
 			Arch:    []string(nil),
 			URL:     "",
 			License: []string(nil),
-			Groups:  []string(nil),
 			Depends: []ArchDistroString{
 				{Arch: "", Value: "libc++=6.0.0-1"},
 			},
@@ -132,9 +118,6 @@ var libcpp = &Srcinfo{
 			Conflicts:  []ArchDistroString(nil),
 			Replaces:   []ArchDistroString(nil),
 			Backup:     []string(nil),
-			Options:    []string(nil),
-			Install:    "",
-			Changelog:  "",
 		},
 	},
 }
@@ -148,7 +131,6 @@ var libcppPackage = &Package{
 		"MIT",
 		"custom:University of Illinois/NCSA Open Source License",
 	},
-	Groups: []string(nil),
 	Depends: []ArchDistroString{
 		{Arch: "", Value: "libc++abi=6.0.0-1"},
 	},
@@ -157,9 +139,10 @@ var libcppPackage = &Package{
 	Conflicts:  []ArchDistroString(nil),
 	Replaces:   []ArchDistroString(nil),
 	Backup:     []string(nil),
-	Options:    []string(nil),
-	Install:    "",
-	Changelog:  "",
+	Extra: []ExtraField{
+		{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "11E521D646982372EB577A1F8F0871F202119294"}},
+		{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "B6C8F98282B944E3B0D5C2530FC3042E345AD05D"}},
+	},
 }
 
 var libcppABIPackage = &Package{
@@ -174,7 +157,6 @@ var libcppABIPackage = &Package{
 		"MIT",
 		"custom:University of Illinois/NCSA Open Source License",
 	},
-	Groups: []string(nil),
 	Depends: []ArchDistroString{
 		{Arch: "", Value: "gcc-libs"},
 	},
@@ -183,9 +165,10 @@ var libcppABIPackage = &Package{
 	Conflicts:  []ArchDistroString(nil),
 	Replaces:   []ArchDistroString(nil),
 	Backup:     []string(nil),
-	Options:    []string(nil),
-	Install:    "",
-	Changelog:  "",
+	Extra: []ExtraField{
+		{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "11E521D646982372EB577A1F8F0871F202119294"}},
+		{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "B6C8F98282B944E3B0D5C2530FC3042E345AD05D"}},
+	},
 }
 
 var libcppExperimentalPackage = &Package{
@@ -200,7 +183,6 @@ var libcppExperimentalPackage = &Package{
 		"MIT",
 		"custom:University of Illinois/NCSA Open Source License",
 	},
-	Groups: []string(nil),
 	Depends: []ArchDistroString{
 		{Arch: "", Value: "libc++=6.0.0-1"},
 	},
@@ -209,9 +191,10 @@ var libcppExperimentalPackage = &Package{
 	Conflicts:  []ArchDistroString(nil),
 	Replaces:   []ArchDistroString(nil),
 	Backup:     []string(nil),
-	Options:    []string(nil),
-	Install:    "",
-	Changelog:  "",
+	Extra: []ExtraField{
+		{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "11E521D646982372EB577A1F8F0871F202119294"}},
+		{Key: "validpgpkeys", ArchDistroString: ArchDistroString{Value: "B6C8F98282B944E3B0D5C2530FC3042E345AD05D"}},
+	},
 }
 
 func TestLibcpp(t *testing.T) {
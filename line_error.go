@@ -1,15 +1,43 @@
 package srcinfo
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+)
+
+// Errors that may be wrapped by a LineError. Use errors.Is to check for them.
+var (
+	ErrMissingEquals         = errors.New("line does not contain =")
+	ErrEmptyKey              = errors.New("key is empty")
+	ErrUnknownKey            = errors.New("unknown key")
+	ErrDuplicatePkgbase      = errors.New("duplicate pkgbase")
+	ErrDuplicatePkgname      = errors.New("duplicate pkgname")
+	ErrPkgnameBeforePkgbase  = errors.New("pkgname before pkgbase")
+	ErrFieldBeforePkgbase    = errors.New("field before pkgbase")
+	ErrBaseFieldAfterPkgname = errors.New("pkgbase field after pkgname")
+	ErrInvalidArch           = errors.New("invalid arch")
+	ErrUnknownSuffix         = errors.New("unknown distro or arch suffix")
+	ErrMissingPkgbase        = errors.New("missing pkgbase")
+	ErrMissingPkgname        = errors.New("missing pkgname")
+	ErrMissingPkgver         = errors.New("missing pkgver")
+	ErrMissingPkgrel         = errors.New("missing pkgrel")
+	ErrMissingArch           = errors.New("missing arch")
+	ErrMissingField          = errors.New("missing required field")
 )
 
 // LineError is an error type that stores the line number at which an error
 // occurred as well the full Line that cased the error and an error string.
+//
+// Errors generated by the parser also set Column, Key and Err so that the
+// kind of error can be checked using errors.Is.
 type LineError struct {
 	LineNumber int    // The line number at which the error occurred
 	Line       string // The line that caused the error
 	ErrorStr   string // An error string
+	Column     int    // The column of the key or value at fault, 0 if unknown
+	Key        string // The key that caused the error, if any
+	Err        error  // The kind of error, one of the Err variables
 }
 
 // Error Returns an error string in the format:
@@ -18,12 +46,18 @@ func (le LineError) Error() string {
 	return fmt.Sprintf("Line %d: %s: %s", le.LineNumber, le.ErrorStr, le.Line)
 }
 
+// Unwrap returns the kind of error, allowing errors.Is to be used with the Err
+// variables.
+func (le LineError) Unwrap() error {
+	return le.Err
+}
+
 // Error Returns a new LineError
 func Error(LineNumber int, Line string, ErrorStr string) *LineError {
 	return &LineError{
-		LineNumber,
-		Line,
-		ErrorStr,
+		LineNumber: LineNumber,
+		Line:       Line,
+		ErrorStr:   ErrorStr,
 	}
 }
 
@@ -31,8 +65,85 @@ func Error(LineNumber int, Line string, ErrorStr string) *LineError {
 // fmt.Printf.
 func Errorf(LineNumber int, Line string, ErrorStr string, args ...interface{}) *LineError {
 	return &LineError{
-		LineNumber,
-		Line,
-		fmt.Sprintf(ErrorStr, args...),
+		LineNumber: LineNumber,
+		Line:       Line,
+		ErrorStr:   fmt.Sprintf(ErrorStr, args...),
 	}
 }
+
+// keyError is used by the parser to carry the kind of an error and the key
+// that caused it until it can be turned into a LineError.
+type keyError struct {
+	err     error
+	key     string
+	inValue bool // The value is at fault rather than the key
+	msg     string
+}
+
+func (ke *keyError) Error() string {
+	return ke.msg
+}
+
+func (ke *keyError) Unwrap() error {
+	return ke.err
+}
+
+func keyErrorf(err error, key string, format string, args ...interface{}) error {
+	return &keyError{err, key, false, fmt.Sprintf(format, args...)}
+}
+
+func valueErrorf(err error, key string, format string, args ...interface{}) error {
+	return &keyError{err, key, true, fmt.Sprintf(format, args...)}
+}
+
+// lineError creates a LineError for line n from an error returned by the
+// parser. line is the line as it was read.
+func lineError(n int, line string, err error) *LineError {
+	trimmed := strings.TrimSpace(line)
+	le := Error(n, trimmed, err.Error())
+
+	var ke *keyError
+	if !errors.As(err, &ke) {
+		return le
+	}
+
+	le.Err = ke.err
+	le.Key = ke.key
+	le.Column = len(line) - len(strings.TrimLeft(line, " \t")) + 1
+
+	if ke.inValue {
+		if eq := strings.IndexByte(trimmed, '='); eq != -1 {
+			value := strings.TrimLeft(trimmed[eq+1:], " \t")
+			le.Column += len(trimmed) - len(value)
+		}
+	}
+
+	return le
+}
+
+// ErrorList is a list of LineErrors. It is returned when parsing with
+// ContinueOnError and at least one error was found.
+type ErrorList []*LineError
+
+// Error returns the error string of each LineError, one per line.
+func (el ErrorList) Error() string {
+	strs := make([]string, 0, len(el))
+
+	for _, err := range el {
+		strs = append(strs, err.Error())
+	}
+
+	return strings.Join(strs, "\n")
+}
+
+// Unwrap returns the errors contained in the list so that errors.Is and
+// errors.As can inspect each of them.
+func (el ErrorList) Unwrap() []error {
+	errs := make([]error, 0, len(el))
+
+	for _, err := range el {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
@@ -1,6 +1,7 @@
 package srcinfo
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -43,3 +44,46 @@ func TestLineErrorf(t *testing.T) {
 
 	t.Logf("error: %#v generated message: %s", err, err.Error())
 }
+
+func TestErrorList(t *testing.T) {
+	first := Error(1, "a", "first")
+	second := Error(2, "b", "second")
+	errs := ErrorList{first, second}
+
+	expected := first.Error() + "\n" + second.Error()
+	if errs.Error() != expected {
+		t.Errorf("Error should be \"%s\" but was \"%s\"", expected, errs.Error())
+	}
+
+	var lineError *LineError
+	if !errors.As(errs, &lineError) || lineError != first {
+		t.Errorf("errors.As should find the first LineError")
+	}
+}
+
+func TestLineErrorKind(t *testing.T) {
+	data := "pkgbase = foo\n\tpkgver = 1\n\npkgname = foo\n  pkgname =  foo\n"
+
+	_, err := Parse(data)
+	if !errors.Is(err, ErrDuplicatePkgname) {
+		t.Fatalf("error should be %v but was %v", ErrDuplicatePkgname, err)
+	}
+
+	var lineError *LineError
+	if !errors.As(err, &lineError) {
+		t.Fatalf("expected a LineError but got: %v", err)
+	}
+
+	if lineError.LineNumber != 5 || lineError.Column != 14 || lineError.Key != "pkgname" {
+		t.Errorf("unexpected line, column or key: %#v", lineError)
+	}
+
+	_, err = ParseWithOptions("pkgbase = foo\n\tfoo_x = 1\n", ParseOptions{Strict: true})
+	if !errors.As(err, &lineError) || !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("error should be %v but was %v", ErrUnknownKey, err)
+	}
+
+	if lineError.Column != 2 || lineError.Key != "foo_x" {
+		t.Errorf("unexpected column or key: %#v", lineError)
+	}
+}
@@ -0,0 +1,136 @@
+package srcinfo
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+)
+
+// Maintainer is a parsed entry of the maintainer field.
+type Maintainer struct {
+	Name  string
+	Email string
+}
+
+// ParseMaintainer parses a maintainer in the form "Name <email>". The rules of
+// net/mail are followed, so the name may be quoted and a bare email address is
+// also accepted.
+func ParseMaintainer(str string) (Maintainer, error) {
+	addr, err := mail.ParseAddress(str)
+	if err != nil {
+		return Maintainer{}, fmt.Errorf("Invalid maintainer \"%s\": %s", str, err.Error())
+	}
+
+	return Maintainer{addr.Name, addr.Address}, nil
+}
+
+// String formats the maintainer as "Name <email>", or just the email if there
+// is no name.
+func (m Maintainer) String() string {
+	if m.Name == "" {
+		return m.Email
+	}
+
+	return m.Name + " <" + m.Email + ">"
+}
+
+// key identifies a maintainer regardless of name or the case of the email.
+func (m Maintainer) key() string {
+	return strings.ToLower(m.Email)
+}
+
+// Maintainers parses every entry of the maintainer field, stopping at the
+// first malformed entry.
+func (si *Srcinfo) Maintainers() ([]Maintainer, error) {
+	maintainers := make([]Maintainer, 0, len(si.Maintainer))
+
+	for _, str := range si.Maintainer {
+		maintainer, err := ParseMaintainer(str)
+		if err != nil {
+			return nil, err
+		}
+
+		maintainers = append(maintainers, maintainer)
+	}
+
+	return maintainers, nil
+}
+
+// ValidateMaintainers checks every entry of the maintainer field, returning
+// an error for each malformed entry joined with errors.Join.
+func (si *Srcinfo) ValidateMaintainers() error {
+	var errs []error
+
+	for _, str := range si.Maintainer {
+		if _, err := ParseMaintainer(str); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", si.Pkgbase, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MaintainerPackages lists the pkgbases maintained by a maintainer.
+type MaintainerPackages struct {
+	Maintainer Maintainer // The zero value for packages without a maintainer
+	Pkgbases   []string
+}
+
+// GroupByMaintainer groups the srcinfos of a repository by maintainer.
+// Maintainers are matched by email, ignoring case, and the name of the first
+// entry seen for an email is used. A srcinfo with several maintainers is
+// listed under each of them.
+//
+// The groups are sorted by email, the group of srcinfos without any valid
+// maintainer coming first. Malformed entries are skipped and returned as an
+// error, see ValidateMaintainers.
+func GroupByMaintainer(srcinfos []*Srcinfo) ([]MaintainerPackages, error) {
+	groups := make(map[string]*MaintainerPackages)
+	var errs []error
+
+	for _, si := range srcinfos {
+		errs = append(errs, si.ValidateMaintainers())
+		found := false
+
+		for _, str := range si.Maintainer {
+			maintainer, err := ParseMaintainer(str)
+			if err != nil {
+				continue
+			}
+
+			found = true
+			group, ok := groups[maintainer.key()]
+			if !ok {
+				group = &MaintainerPackages{Maintainer: maintainer}
+				groups[maintainer.key()] = group
+			}
+
+			group.Pkgbases = append(group.Pkgbases, si.Pkgbase)
+		}
+
+		if !found {
+			group, ok := groups[""]
+			if !ok {
+				group = &MaintainerPackages{}
+				groups[""] = group
+			}
+
+			group.Pkgbases = append(group.Pkgbases, si.Pkgbase)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]MaintainerPackages, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, *groups[key])
+	}
+
+	return list, errors.Join(errs...)
+}
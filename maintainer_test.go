@@ -0,0 +1,101 @@
+package srcinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMaintainer(t *testing.T) {
+	tests := []struct {
+		str        string
+		maintainer Maintainer
+		formatted  string
+	}{
+		{"Foo Bar <foo@example.com>", Maintainer{"Foo Bar", "foo@example.com"}, "Foo Bar <foo@example.com>"},
+		{"\"Bar, Foo\" <foo@example.com>", Maintainer{"Bar, Foo", "foo@example.com"}, "Bar, Foo <foo@example.com>"},
+		{"foo@example.com", Maintainer{"", "foo@example.com"}, "foo@example.com"},
+		{"<foo@example.com>", Maintainer{"", "foo@example.com"}, "foo@example.com"},
+	}
+
+	for _, test := range tests {
+		maintainer, err := ParseMaintainer(test.str)
+		if err != nil {
+			t.Errorf("%s: %s", test.str, err)
+			continue
+		}
+
+		if maintainer != test.maintainer {
+			t.Errorf("%s: expected %#v got %#v", test.str, test.maintainer, maintainer)
+		}
+
+		if maintainer.String() != test.formatted {
+			t.Errorf("%s: expected %s got %s", test.str, test.formatted, maintainer.String())
+		}
+	}
+
+	for _, str := range []string{"", "Foo Bar", "Foo <foo>", "Foo <foo@example.com"} {
+		if _, err := ParseMaintainer(str); err == nil {
+			t.Errorf("%s: should have errored", str)
+		}
+	}
+}
+
+func TestValidateMaintainers(t *testing.T) {
+	srcinfo := &Srcinfo{}
+	srcinfo.Pkgbase = "foo"
+	srcinfo.Maintainer = []string{"Foo <foo@example.com>", "Bar", "Baz <baz>"}
+
+	err := srcinfo.ValidateMaintainers()
+	if err == nil {
+		t.Fatal("should have errored")
+	}
+
+	if len(err.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+		t.Errorf("expected 2 errors got: %s", err)
+	}
+
+	if _, err := srcinfo.Maintainers(); err == nil {
+		t.Error("should have errored")
+	}
+
+	srcinfo.Maintainer = srcinfo.Maintainer[:1]
+	if err := srcinfo.ValidateMaintainers(); err != nil {
+		t.Error(err)
+	}
+
+	maintainers, err := srcinfo.Maintainers()
+	if err != nil || len(maintainers) != 1 || maintainers[0].Email != "foo@example.com" {
+		t.Errorf("unexpected maintainers %#v: %v", maintainers, err)
+	}
+}
+
+func TestGroupByMaintainer(t *testing.T) {
+	newSrcinfo := func(pkgbase string, maintainers ...string) *Srcinfo {
+		si := &Srcinfo{}
+		si.Pkgbase = pkgbase
+		si.Maintainer = maintainers
+		return si
+	}
+
+	srcinfos := []*Srcinfo{
+		newSrcinfo("a", "Foo <foo@example.com>"),
+		newSrcinfo("b", "Bar <bar@example.com>", "Foo <FOO@example.com>"),
+		newSrcinfo("c"),
+		newSrcinfo("d", "broken"),
+	}
+
+	groups, err := GroupByMaintainer(srcinfos)
+	if err == nil {
+		t.Error("malformed maintainer should have errored")
+	}
+
+	expected := []MaintainerPackages{
+		{Maintainer{}, []string{"c", "d"}},
+		{Maintainer{"Bar", "bar@example.com"}, []string{"b"}},
+		{Maintainer{"Foo", "foo@example.com"}, []string{"a", "b"}},
+	}
+
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %#v got %#v", expected, groups)
+	}
+}
@@ -13,11 +13,27 @@ type parser struct {
 
 	// seenPkgnames is a set of pkgnames we have seen
 	seenPkgnames map[string]struct{}
+
+	// opts are the options the srcinfo is being parsed with.
+	opts ParseOptions
+
+	// pkgbaseLine is the line number and text of the pkgbase header, used to
+	// report missing fields.
+	pkgbaseLine     int
+	pkgbaseLineText string
+
+	// seenKeys is the set of keys, without suffixes, seen in the pkgbase
+	// section.
+	seenKeys map[string]struct{}
+
+	// positionCounts counts the values seen for each field of each section
+	// when recording positions.
+	positionCounts map[FieldRef]int
 }
 
 func (psr *parser) currentPackage() (*Package, error) {
 	if psr.srcinfo.Pkgbase == "" {
-		return nil, fmt.Errorf("Not in pkgbase or pkgname")
+		return nil, keyErrorf(ErrFieldBeforePkgbase, "", "Not in pkgbase or pkgname")
 	} else if len(psr.srcinfo.Packages) == 0 {
 		return &psr.srcinfo.Package, nil
 	} else {
@@ -31,17 +47,17 @@ func (psr *parser) setHeaderOrField(key, value string) error {
 	switch key {
 	case "pkgbase":
 		if psr.srcinfo.Pkgbase != "" {
-			return fmt.Errorf("key \"%s\" can not occur after pkgbase or pkgname", key)
+			return keyErrorf(ErrDuplicatePkgbase, key, "key \"%s\" can not occur after pkgbase or pkgname", key)
 		}
 
 		pkgbase.Pkgbase = value
 		return nil
 	case "pkgname":
 		if psr.srcinfo.Pkgbase == "" {
-			return fmt.Errorf("key \"%s\" can not occur before pkgbase", key)
+			return keyErrorf(ErrPkgnameBeforePkgbase, key, "key \"%s\" can not occur before pkgbase", key)
 		}
 		if _, ok := psr.seenPkgnames[value]; ok {
-			return fmt.Errorf("pkgname \"%s\" can not occur more than once", value)
+			return valueErrorf(ErrDuplicatePkgname, key, "pkgname \"%s\" can not occur more than once", value)
 		}
 		psr.seenPkgnames[value] = struct{}{}
 
@@ -50,7 +66,7 @@ func (psr *parser) setHeaderOrField(key, value string) error {
 	}
 
 	if psr.srcinfo.Pkgbase == "" {
-		return fmt.Errorf("key \"%s\" can not occur before pkgbase or pkgname", key)
+		return keyErrorf(ErrFieldBeforePkgbase, key, "key \"%s\" can not occur before pkgbase or pkgname", key)
 	}
 
 	return psr.setField(key, value)
@@ -62,192 +78,101 @@ func (psr *parser) setField(archKey, value string) error {
 		return err
 	}
 
-	pkgbase := &psr.srcinfo.PackageBase
-	key, distro, arch := splitDistroArchFromKey(psr.srcinfo.Arch, archKey)
-	err = checkArch(psr.srcinfo.Arch, archKey, arch)
+	key, distro, arch, err := psr.splitKey(archKey)
 	if err != nil {
 		return err
 	}
 
-	if value == "" {
-		value = EmptyOverride
-	}
-
-	// pkgbase only + not arch dependent
-	found := true
-	switch archKey {
-	case "pkgver":
-		pkgbase.Pkgver = value
-	case "pkgrel":
-		pkgbase.Pkgrel = value
-	case "epoch":
-		pkgbase.Epoch = value
-	case "mask":
-		pkgbase.Mask = append(pkgbase.Mask, value)
-	case "compatible":
-		pkgbase.Compatible = append(pkgbase.Compatible, value)
-	case "incompatible":
-		pkgbase.Incompatible = append(pkgbase.Incompatible, value)
-	case "maintainer":
-		pkgbase.Maintainer = append(pkgbase.Maintainer, value)
-	case "noextract":
-		pkgbase.NoExtract = append(pkgbase.NoExtract, value)
-	case "nosubmodules":
-		pkgbase.NoSubmodules = append(pkgbase.NoSubmodules, value)
-	default:
-		found = false
-	}
-
-	if found {
-		if len(psr.srcinfo.Packages) > 0 {
-			return fmt.Errorf("key \"%s\" can not occur after pkgname", archKey)
-		}
-
-		return nil
+	err = checkArch(psr.opts.ArchAliases, psr.srcinfo.Arch, archKey, arch)
+	if err != nil {
+		return err
 	}
 
-	// pkgbase only + arch dependent
-	found = true
-	switch key {
-	case "source":
-		pkgbase.Source = append(pkgbase.Source, ArchDistroString{arch, distro, value})
-	case "md5sums":
-		pkgbase.MD5Sums = append(pkgbase.MD5Sums, ArchDistroString{arch, distro, value})
-	case "sha1sums":
-		pkgbase.SHA1Sums = append(pkgbase.SHA1Sums, ArchDistroString{arch, distro, value})
-	case "sha224sums":
-		pkgbase.SHA224Sums = append(pkgbase.SHA224Sums, ArchDistroString{arch, distro, value})
-	case "sha256sums":
-		pkgbase.SHA256Sums = append(pkgbase.SHA256Sums, ArchDistroString{arch, distro, value})
-	case "sha384sums":
-		pkgbase.SHA384Sums = append(pkgbase.SHA384Sums, ArchDistroString{arch, distro, value})
-	case "sha512sums":
-		pkgbase.SHA512Sums = append(pkgbase.SHA512Sums, ArchDistroString{arch, distro, value})
-	case "b2sums":
-		pkgbase.B2Sums = append(pkgbase.B2Sums, ArchDistroString{arch, distro, value})
-	case "makedepends":
-		pkgbase.MakeDepends = append(pkgbase.MakeDepends, ArchDistroString{arch, distro, value})
-	case "makeconflicts":
-		pkgbase.MakeConflicts = append(pkgbase.MakeConflicts, ArchDistroString{arch, distro, value})
-	default:
-		found = false
+	if value == "" {
+		value = EmptyOverride
 	}
 
-	if found {
-		if len(psr.srcinfo.Packages) > 0 {
-			return fmt.Errorf("key \"%s\" can not occur after pkgname", archKey)
+	field, ok := psr.dialect().field(key)
+	if !ok || (!field.ArchDistro && key != archKey) {
+		if psr.strict() {
+			return keyErrorf(ErrUnknownKey, archKey, "Unknown key \"%s\"", archKey)
 		}
 
+		pkg.Extra = append(pkg.Extra, ExtraField{key, ArchDistroString{arch, distro, value}})
 		return nil
 	}
 
-	// pkgbase or pkgname + not arch dependent
-	found = true
-	switch archKey {
-	case "pkgdesc":
-		pkg.Pkgdesc = value
-	case "url":
-		pkg.URL = value
-	case "priority":
-		pkg.Priority = value
-	case "arch":
-		pkg.Arch = append(pkg.Arch, value)
-	case "license":
-		pkg.License = append(pkg.License, value)
-	case "backup":
-		pkg.Backup = append(pkg.Backup, value)
-	case "repology":
-		pkg.Repology = append(pkg.Repology, value)
-	default:
-		found = false
-	}
-
-	if found {
-		return nil
+	if field.Scope == ScopeBase && len(psr.srcinfo.Packages) > 0 {
+		return keyErrorf(ErrBaseFieldAfterPkgname, archKey, "key \"%s\" can not occur after pkgname", archKey)
 	}
 
-	// pkgbase or pkgname + arch dependent
-	switch key {
-	case "gives":
-		pkg.Gives = append(pkg.Gives, ArchDistroString{arch, distro, value})
-	case "depends":
-		pkg.Depends = append(pkg.Depends, ArchDistroString{arch, distro, value})
-	case "checkdepends":
-		pkg.CheckDepends = append(pkg.CheckDepends, ArchDistroString{arch, distro, value})
-	case "optdepends":
-		pkg.OptDepends = append(pkg.OptDepends, ArchDistroString{arch, distro, value})
-	case "pacdeps":
-		pkg.Pacdeps = append(pkg.Pacdeps, ArchDistroString{arch, distro, value})
-	case "checkconflicts":
-		pkg.CheckConflicts = append(pkg.CheckConflicts, ArchDistroString{arch, distro, value})
-	case "conflicts":
-		pkg.Conflicts = append(pkg.Conflicts, ArchDistroString{arch, distro, value})
-	case "provides":
-		pkg.Provides = append(pkg.Provides, ArchDistroString{arch, distro, value})
-	case "breaks":
-		pkg.Breaks = append(pkg.Breaks, ArchDistroString{arch, distro, value})
-	case "replaces":
-		pkg.Replaces = append(pkg.Replaces, ArchDistroString{arch, distro, value})
-	case "enhances":
-		pkg.Enhances = append(pkg.Enhances, ArchDistroString{arch, distro, value})
-	case "recommends":
-		pkg.Recommends = append(pkg.Recommends, ArchDistroString{arch, distro, value})
-	case "suggests":
-		pkg.Suggests = append(pkg.Suggests, ArchDistroString{arch, distro, value})
+	if len(psr.srcinfo.Packages) == 0 {
+		psr.seenKeys[key] = struct{}{}
 	}
 
+	field.set(psr.srcinfo, pkg, ArchDistroString{arch, distro, value})
 	return nil
 }
 
-func parse(data string) (*Srcinfo, error) {
-	psr := &parser{
-		&Srcinfo{},
-		make(map[string]struct{}),
+func newParser(opts ParseOptions) *parser {
+	return &parser{
+		srcinfo:      &Srcinfo{registry: opts.Registry.clone(), aliases: opts.ArchAliases.clone()},
+		seenPkgnames: make(map[string]struct{}),
+		seenKeys:     make(map[string]struct{}),
+		opts:         opts,
 	}
+}
 
-	lines := strings.Split(data, "\n")
-
-	for n, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
+// parseLine handles a single line of srcinfo input. n is the line number of
+// the line, starting at 1.
+func (psr *parser) parseLine(n int, line string) *LineError {
+	raw := line
+	line = strings.TrimSpace(line)
 
-		key, value, err := splitPair(line)
-		if err != nil {
-			return nil, Error(n+1, line, err.Error())
-		}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
 
-		err = psr.setHeaderOrField(key, value)
-		if err != nil {
-			return nil, Error(n+1, line, err.Error())
-		}
+	key, value, err := splitPair(line)
+	if err != nil {
+		return lineError(n, raw, err)
 	}
 
-	if psr.srcinfo.Pkgbase == "" {
-		return nil, fmt.Errorf("No pkgbase field")
+	err = psr.setHeaderOrField(key, value)
+	if err != nil {
+		return lineError(n, raw, err)
 	}
 
-	if len(psr.srcinfo.Packages) == 0 {
-		return nil, fmt.Errorf("No pkgname field")
+	if key == "pkgbase" {
+		psr.pkgbaseLine = n
+		psr.pkgbaseLineText = line
 	}
 
-	if psr.srcinfo.Pkgver == "" {
-		return nil, fmt.Errorf("No pkgver field")
+	if psr.opts.Positions != nil {
+		psr.recordPosition(n, raw, key, value)
 	}
 
-	if psr.srcinfo.Pkgrel == "" {
-		// Needed because Pacstall implicitely has `1` for empty pkgrel.
-		psr.srcinfo.Pkgrel = "1"
+	return nil
+}
+
+// finish checks for required fields and fills in implicit values once all
+// lines have been parsed. lastLine is the number of the final line of input
+// and is used to report errors when there is no pkgbase to point at.
+//
+// All missing fields are returned so callers may choose to report only the
+// first one.
+func (psr *parser) finish(lastLine int) ErrorList {
+	if psr.srcinfo.Pkgbase == "" {
+		return ErrorList{lineError(lastLine, "", keyErrorf(ErrMissingPkgbase, "pkgbase", "No pkgbase field"))}
 	}
 
-	if len(psr.srcinfo.Arch) == 0 {
-		psr.srcinfo.Arch = []string{"any"}
-		// return nil, fmt.Errorf("No arch field")
+	var errs ErrorList
+
+	if len(psr.srcinfo.Packages) == 0 {
+		errs = append(errs, lineError(psr.pkgbaseLine, psr.pkgbaseLineText, keyErrorf(ErrMissingPkgname, "pkgname", "No pkgname field")))
 	}
 
-	return psr.srcinfo, nil
+	return append(errs, psr.checkRequired()...)
 }
 
 // splitPair splits a key value string in the form of "key = value",
@@ -256,32 +181,49 @@ func splitPair(line string) (string, string, error) {
 	split := strings.SplitN(line, "=", 2)
 
 	if len(split) != 2 {
-		return "", "", fmt.Errorf("Line does not contain =")
+		return "", "", keyErrorf(ErrMissingEquals, "", "Line does not contain =")
 	}
 
 	key := strings.TrimSpace(split[0])
 	value := strings.TrimSpace(split[1])
 
 	if key == "" {
-		return "", "", fmt.Errorf("Key is empty")
+		return "", "", keyErrorf(ErrEmptyKey, "", "Key is empty")
 	}
 
 	return key, value, nil
 }
 
+// splitKey splits up an architecture or distro dependent field name using the
+// Registry if one is set.
+func (psr *parser) splitKey(key string) (string, string, string, error) {
+	if !psr.dialect().DistroSuffixes {
+		name, arch, _ := strings.Cut(key, "_")
+		return name, "", arch, nil
+	}
+
+	if psr.opts.Registry != nil {
+		return psr.opts.Registry.splitKey(psr.opts.ArchAliases, psr.srcinfo.Arch, key)
+	}
+
+	name, distro, arch := splitDistroArchFromKey(psr.opts.ArchAliases, psr.srcinfo.Arch, key)
+	return name, distro, arch, nil
+}
+
 // splitArchFromKey splits up architecture dependent field names, separating
 // the field name from the architecture they depend on.
-func splitDistroArchFromKey(arches []string, key string) ( /* name */ string /* distro */, string /* arch */, string) {
+func splitDistroArchFromKey(aliases *ArchAliases, arches []string, key string) ( /* name */ string /* distro */, string /* arch */, string) {
 	split := strings.SplitN(key, "_", 3)
 
 	// possible cases: name_distro_arch, name_distro, name_arch
 	if len(split) == 3 {
 		arch := split[2]
-		// treat cases like name_x86_64
-		if checkArch(arches, key, arch) != nil {
+		// treat cases like name_x86_64, "any" is always treated as an arch so
+		// that checkArch can reject it
+		if !aliases.isAny(arch) && checkArch(aliases, arches, key, arch) != nil {
 			// possibly in a case like name_x86_64 or invalid arch
 			arch = split[1] + "_" + split[2]
-			if checkArch(arches, key, arch) != nil {
+			if checkArch(aliases, arches, key, arch) != nil {
 				// invalid arch so it's a distro
 				return split[0], arch, ""
 			}
@@ -296,7 +238,7 @@ func splitDistroArchFromKey(arches []string, key string) ( /* name */ string /*
 
 	// name_arch, name_distro
 	if len(split) == 2 {
-		if checkArch(arches, key, split[1]) == nil {
+		if aliases.isAny(split[1]) || checkArch(aliases, arches, key, split[1]) == nil {
 			return split[0], "", split[1]
 		}
 
@@ -308,53 +250,100 @@ func splitDistroArchFromKey(arches []string, key string) ( /* name */ string /*
 
 // checkArg checks that the arch from an arch dependent string is actually
 // defined inside of the srcinfo and specifically disallows the arch "any" as it
-// is not a real arch
-func checkArch(arches []string, key string, arch string) error {
+// is not a real arch. Aliases such as amd64 and x86_64 are treated as the same
+// arch, see ArchAliases.
+func checkArch(aliases *ArchAliases, arches []string, key string, arch string) error {
 	if arch == "" {
 		return nil
 	}
 
-	if arch == "any" {
-		return fmt.Errorf("Invalid key \"%s\" arch \"%s\" is not allowed", key, arch)
+	if aliases.isAny(arch) {
+		return keyErrorf(ErrInvalidArch, key, "Invalid key \"%s\" arch \"%s\" is not allowed", key, arch)
 	}
 
 	for _, a := range arches {
-		if a == arch {
+		if aliases.Equal(a, arch) {
 			return nil
 		}
 	}
 
-	return fmt.Errorf("Invalid key \"%s\" unsupported arch \"%s\"", key, arch)
+	return keyErrorf(ErrInvalidArch, key, "Invalid key \"%s\" unsupported arch \"%s\"", key, arch)
 }
 
-// ParseFile parses a srcinfo file as specified by path.
+// ParseOptions changes how a srcinfo is parsed. The zero value gives the
+// same behaviour as Parse.
+type ParseOptions struct {
+	// ContinueOnError makes the parser recover at the next line after an
+	// error instead of stopping. The partially parsed Srcinfo is returned
+	// along with an ErrorList containing every error that was found.
+	ContinueOnError bool
+
+	// Strict makes unknown keys an error instead of storing them in
+	// Package.Extra.
+	Strict bool
+
+	// Dialect sets the rules the srcinfo must follow. If nil PacstallDialect
+	// is used.
+	Dialect *Dialect
+
+	// Registry, if not nil, is used to split distro and arch suffixes from
+	// keys. Keys with unknown suffixes are then an error. A copy is kept by
+	// the Srcinfo for Targets.
+	Registry *Registry
+
+	// ArchAliases, if not nil, replaces the built in arch aliases. A copy is
+	// kept by the Srcinfo for merging, resolving and pairing checksums.
+	ArchAliases *ArchAliases
+
+	// Positions, if not nil, is filled in with the position of every parsed
+	// value.
+	Positions Positions
+}
+
+// ParseFile parses a srcinfo file as specified by path. The file is read
+// line by line, see Decoder.
 func ParseFile(path string) (*Srcinfo, error) {
-	file, err := os.ReadFile(path)
+	return ParseFileWithOptions(path, ParseOptions{})
+}
+
+// ParseFileWithOptions parses a srcinfo file as specified by path using the
+// given options.
+func ParseFileWithOptions(path string, opts ParseOptions) (*Srcinfo, error) {
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to read file: %s: %s", path, err.Error())
 	}
+	defer file.Close()
 
-	return Parse(string(file))
+	dec := NewDecoder(file)
+	dec.SetOptions(opts)
+	return dec.Decode()
 }
 
 // Parse parses a srcinfo in string form. Parsing will fail if:
 //
 //	A srcinfo does not contain all required fields
 //	The same pkgname is specified more then once
-//	arch is missing
 //	pkgver is mising
-//	pkgrel is missing
 //	An architecture specific field is defined for an architecture that does not exist
-//	An unknown key is specified
-//	An empty value is specified
+//	An unknown key is specified and ParseOptions.Strict is set
 //
 // Required fields are:
 //
 //	pkgbase
 //	pkname
-//	arch
-//	pkgrel
 //	pkgver
+//
+// The srcinfo is parsed using PacstallDialect, so a missing pkgrel defaults to
+// 1 and a missing arch defaults to any. Use ParseWithOptions and ArchDialect to
+// parse srcinfos generated by makepkg.
 func Parse(data string) (*Srcinfo, error) {
-	return parse(data)
+	return ParseWithOptions(data, ParseOptions{})
+}
+
+// ParseWithOptions parses a srcinfo in string form using the given options.
+func ParseWithOptions(data string, opts ParseOptions) (*Srcinfo, error) {
+	dec := NewDecoder(strings.NewReader(data))
+	dec.SetOptions(opts)
+	return dec.Decode()
 }
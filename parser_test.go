@@ -7,7 +7,7 @@ import (
 func TestCurrentPackage(t *testing.T) {
 	srcinfo := &Srcinfo{}
 	splitpkg := &Package{}
-	psr := &parser{srcinfo, make(map[string]struct{})}
+	psr := &parser{srcinfo: srcinfo, seenPkgnames: make(map[string]struct{})}
 
 	_, err := psr.currentPackage()
 	if err == nil {
@@ -72,10 +72,79 @@ func TestSplitPair(t *testing.T) {
 
 func TestSetField(t *testing.T) {
 	srcinfo := &Srcinfo{}
-	psr := &parser{srcinfo, make(map[string]struct{})}
+	psr := &parser{srcinfo: srcinfo, seenPkgnames: make(map[string]struct{})}
 
 	err := psr.setField("install", "foo")
 	if err == nil {
 		t.Errorf("setField should have errored due to no header but did not")
 	}
 }
+
+func TestContinueOnError(t *testing.T) {
+	data := `pkgbase = foo
+	arch = x86_64
+	= foo
+pkgbase = bar
+	url = https://example.com
+
+pkgname = foo
+	depends = bar
+pkgname = foo
+	noextract = baz
+`
+
+	srcinfo, err := ParseWithOptions(data, ParseOptions{ContinueOnError: true})
+	errs, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("expected an ErrorList but got: %v", err)
+	}
+
+	lines := []int{3, 4, 9, 10, 1}
+	if len(errs) != len(lines) {
+		t.Fatalf("expected %d errors but got %d: %s", len(lines), len(errs), errs)
+	}
+
+	for n, line := range lines {
+		if errs[n].LineNumber != line {
+			t.Errorf("error %d should be on line %d but was on line %d: %s", n, line, errs[n].LineNumber, errs[n])
+		}
+	}
+
+	if srcinfo == nil {
+		t.Fatalf("expected a partial srcinfo")
+	}
+
+	if srcinfo.URL != "https://example.com" {
+		t.Errorf("url should be parsed after an error but was \"%s\"", srcinfo.URL)
+	}
+
+	if len(srcinfo.Packages) != 1 || len(srcinfo.Packages[0].Depends) != 1 {
+		t.Errorf("pkgname foo should be parsed after an error: %#v", srcinfo.Packages)
+	}
+}
+
+func TestContinueOnErrorMissingFields(t *testing.T) {
+	_, err := ParseWithOptions("\n", ParseOptions{ContinueOnError: true})
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("expected one error but got: %v", err)
+	}
+
+	if errs[0].LineNumber != 2 {
+		t.Errorf("missing pkgbase should point at the end of the file but was on line %d", errs[0].LineNumber)
+	}
+}
+
+func TestMissingFieldLineError(t *testing.T) {
+	data := "# comment\npkgbase = foo\n\tpkgver = 1\n"
+
+	_, err := Parse(data)
+	lineError, ok := err.(*LineError)
+	if !ok {
+		t.Fatalf("expected a LineError but got: %v", err)
+	}
+
+	if lineError.LineNumber != 2 || lineError.Line != "pkgbase = foo" {
+		t.Errorf("missing pkgname should point at the pkgbase line but got: %s", lineError)
+	}
+}
@@ -0,0 +1,75 @@
+package srcinfo
+
+import (
+	"strings"
+)
+
+// Position describes where a value was found in the srcinfo. Line and Column
+// start at 1 and columns are counted in bytes. EndColumn is the column just
+// after the value, so it equals Column for an empty value.
+type Position struct {
+	Line      int
+	Column    int
+	EndColumn int
+}
+
+// FieldRef identifies a single parsed value.
+//
+// Pkgname is the package whose section the value was found in, or an empty
+// string for the pkgbase section. Key is the field name without any arch or
+// distro suffix. Index is the position of the value among all values of Key
+// in that section, which for known fields is the index into the matching
+// slice of Package or PackageBase. Single valued fields always use index 0.
+type FieldRef struct {
+	Pkgname string
+	Key     string
+	Index   int
+}
+
+// Positions is a side table mapping each parsed value to where it was found.
+// It is filled in by the parser when set in ParseOptions:
+//
+//	positions := srcinfo.Positions{}
+//	info, err := srcinfo.ParseWithOptions(data, srcinfo.ParseOptions{Positions: positions})
+type Positions map[FieldRef]Position
+
+// Lookup returns the position of a value, see FieldRef.
+func (p Positions) Lookup(pkgname, key string, index int) (Position, bool) {
+	pos, ok := p[FieldRef{pkgname, key, index}]
+	return pos, ok
+}
+
+// recordPosition stores the position of the value of a line that has just
+// been parsed. line is the line as it was read.
+func (psr *parser) recordPosition(n int, line, key, value string) {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	trimmed := strings.TrimSpace(line)
+	column := indent + len(trimmed) - len(value) + 1
+
+	ref := FieldRef{Key: key}
+	single := key == "pkgbase" || key == "pkgname"
+	if !single {
+		ref.Key, _, _, _ = psr.splitKey(key)
+
+		// setting a single valued field again replaces the previous value
+		// and position
+		if field, ok := psr.dialect().field(ref.Key); ok && !field.Multi {
+			single = true
+		}
+	}
+
+	if len(psr.srcinfo.Packages) != 0 {
+		ref.Pkgname = psr.srcinfo.Packages[len(psr.srcinfo.Packages)-1].Pkgname
+	}
+
+	if !single {
+		if psr.positionCounts == nil {
+			psr.positionCounts = make(map[FieldRef]int)
+		}
+
+		ref.Index = psr.positionCounts[ref]
+		psr.positionCounts[FieldRef{ref.Pkgname, ref.Key, 0}]++
+	}
+
+	psr.opts.Positions[ref] = Position{n, column, column + len(value)}
+}
@@ -0,0 +1,52 @@
+package srcinfo
+
+import (
+	"testing"
+)
+
+func TestPositions(t *testing.T) {
+	data := "pkgbase = foo\n" +
+		"\tpkgver = 1\n" +
+		"\tarch = amd64\n" +
+		"\tdepends = bar\n" +
+		"\tdepends_jammy = baz\n" +
+		"\tinstall = foo.install\n" +
+		"\n" +
+		"pkgname = foo\n" +
+		"\tpkgdesc =\n" +
+		"  depends_amd64   =   qux  \n"
+
+	positions := Positions{}
+	_, err := ParseWithOptions(data, ParseOptions{Positions: positions})
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	tests := []struct {
+		ref FieldRef
+		pos Position
+	}{
+		{FieldRef{"", "pkgbase", 0}, Position{1, 11, 14}},
+		{FieldRef{"", "pkgver", 0}, Position{2, 11, 12}},
+		{FieldRef{"", "arch", 0}, Position{3, 9, 14}},
+		{FieldRef{"", "depends", 0}, Position{4, 12, 15}},
+		{FieldRef{"", "depends", 1}, Position{5, 18, 21}},
+		{FieldRef{"", "install", 0}, Position{6, 12, 23}},
+		{FieldRef{"foo", "pkgname", 0}, Position{8, 11, 14}},
+		{FieldRef{"foo", "pkgdesc", 0}, Position{9, 11, 11}},
+		{FieldRef{"foo", "depends", 0}, Position{10, 23, 26}},
+	}
+
+	for _, test := range tests {
+		pos, ok := positions.Lookup(test.ref.Pkgname, test.ref.Key, test.ref.Index)
+		if !ok {
+			t.Errorf("no position for %#v", test.ref)
+		} else if pos != test.pos {
+			t.Errorf("position for %#v should be %#v but was %#v", test.ref, test.pos, pos)
+		}
+	}
+
+	if len(positions) != len(tests) {
+		t.Errorf("expected %d positions but got %d: %#v", len(tests), len(positions), positions)
+	}
+}
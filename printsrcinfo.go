@@ -53,6 +53,33 @@ func appendMultiArchValue(buffer *bytes.Buffer, key string, values []ArchDistroS
 	}
 }
 
+func appendExtra(buffer *bytes.Buffer, values []ExtraField) {
+	for _, value := range values {
+		appendMultiArchValue(buffer, value.Key, []ArchDistroString{value.ArchDistroString})
+	}
+}
+
+// appendFields appends every built in field with at least the given scope
+// followed by the Extra fields of pkg.
+func appendFields(buffer *bytes.Buffer, si *Srcinfo, pkg *Package, scope Scope) {
+	for _, field := range builtinFields {
+		if field.Scope < scope {
+			continue
+		}
+
+		switch {
+		case field.str != nil:
+			appendValue(buffer, field.Name, *field.str(si, pkg))
+		case field.strs != nil:
+			appendMultiValue(buffer, field.Name, *field.strs(si, pkg))
+		case field.ads != nil:
+			appendMultiArchValue(buffer, field.Name, *field.ads(si, pkg))
+		}
+	}
+
+	appendExtra(buffer, pkg.Extra)
+}
+
 // String generates a string that should be similar to the srcinfo data used to
 // create this Srcinfo struct. Fields will be printed in order and with the same
 // whitespace rules that `makepkg --printsrcinfo` uses.
@@ -64,114 +91,75 @@ func appendMultiArchValue(buffer *bytes.Buffer, key string, values []ArchDistroS
 //	pkgrel
 //	epoch
 //	url
-//	install
-//	changelog
+//	priority
 //	arch
-//	groups
 //	license
+//	gives
+//	depends
 //	checkdepends
 //	makedepends
-//	depends
 //	optdepends
-//	provides
+//	pacdeps
+//	checkconflicts
+//	makeconflicts
 //	conflicts
+//	provides
+//	breaks
 //	replaces
-//	noextract
-//	options
-//	backup
+//	enhances
+//	recommends
+//	suggests
+//	mask
+//	compatible
+//	incompatible
+//	maintainer
 //	source
-//	validpgpkeys
-//	md5suns
+//	noextract
+//	nosubmodules
+//	md5sums
 //	sha1sums
 //	sha224sums
 //	sha256sums
 //	sha384sums
 //	sha512sums
+//	b2sums
+//	backup
+//	repology
 //
 // The order of each overwritten field is as follows:
 //
 //	pkgdesc
 //	url
-//	install
-//	changelog
+//	priority
 //	arch
-//	groups
 //	license
-//	checkdepends
+//	gives
 //	depends
+//	checkdepends
 //	optdepends
-//	provides
+//	pacdeps
+//	checkconflicts
 //	conflicts
+//	provides
+//	breaks
 //	replaces
-//	options
+//	enhances
+//	recommends
+//	suggests
 //	backup
+//	repology
+//
+// Unknown fields such as install, changelog, groups, options and validpgpkeys
+// are stored in Extra and printed last, in the order they were parsed.
 func (si *Srcinfo) String() string {
 	var buffer bytes.Buffer
 
 	appendHeader(&buffer, "pkgbase", si.Pkgbase)
+	appendFields(&buffer, si, &si.Package, ScopeBase)
 
-	appendValue(&buffer, "pkgdesc", si.Pkgdesc)
-	appendValue(&buffer, "pkgver", si.Pkgver)
-	appendValue(&buffer, "pkgrel", si.Pkgrel)
-	appendValue(&buffer, "epoch", si.Epoch)
-	appendValue(&buffer, "url", si.URL)
-	appendValue(&buffer, "priority", si.Priority)
-	appendMultiValue(&buffer, "arch", si.Arch)
-	appendMultiValue(&buffer, "license", si.License)
-	appendMultiArchValue(&buffer, "gives", si.Gives)
-	appendMultiArchValue(&buffer, "depends", si.Depends)
-	appendMultiArchValue(&buffer, "checkdepends", si.CheckDepends)
-	appendMultiArchValue(&buffer, "makedepends", si.MakeDepends)
-	appendMultiArchValue(&buffer, "optdepends", si.OptDepends)
-	appendMultiArchValue(&buffer, "pacdeps", si.Pacdeps)
-	appendMultiArchValue(&buffer, "checkconflicts", si.CheckConflicts)
-	appendMultiArchValue(&buffer, "makeconflicts", si.MakeConflicts)
-	appendMultiArchValue(&buffer, "conflicts", si.Conflicts)
-	appendMultiArchValue(&buffer, "provides", si.Provides)
-	appendMultiArchValue(&buffer, "breaks", si.Breaks)
-	appendMultiArchValue(&buffer, "replaces", si.Replaces)
-	appendMultiArchValue(&buffer, "enhances", si.Enhances)
-	appendMultiArchValue(&buffer, "recommends", si.Recommends)
-	appendMultiArchValue(&buffer, "suggests", si.Suggests)
-	appendMultiValue(&buffer, "mask", si.Mask)
-	appendMultiValue(&buffer, "compatible", si.Compatible)
-	appendMultiValue(&buffer, "incompatible", si.Incompatible)
-	appendMultiValue(&buffer, "maintainer", si.Maintainer)
-	appendMultiArchValue(&buffer, "source", si.Source)
-	appendMultiValue(&buffer, "noextract", si.NoExtract)
-	appendMultiValue(&buffer, "nosubmodules", si.NoSubmodules)
-	appendMultiArchValue(&buffer, "md5sums", si.MD5Sums)
-	appendMultiArchValue(&buffer, "sha1sums", si.SHA1Sums)
-	appendMultiArchValue(&buffer, "sha224sums", si.SHA224Sums)
-	appendMultiArchValue(&buffer, "sha256sums", si.SHA256Sums)
-	appendMultiArchValue(&buffer, "sha384sums", si.SHA384Sums)
-	appendMultiArchValue(&buffer, "sha512sums", si.SHA512Sums)
-	appendMultiValue(&buffer, "backup", si.Backup)
-	appendMultiValue(&buffer, "repology", si.Repology)
-
-	for n, pkg := range si.Packages {
+	for n := range si.Packages {
 		appendHeader(&buffer, "\npkgname", si.Packages[n].Pkgname)
-
-		appendValue(&buffer, "pkgdesc", pkg.Pkgdesc)
-		appendValue(&buffer, "url", pkg.URL)
-		appendValue(&buffer, "priority", pkg.Priority)
-		appendMultiValue(&buffer, "arch", pkg.Arch)
-		appendMultiValue(&buffer, "license", pkg.License)
-		appendMultiArchValue(&buffer, "gives", pkg.Gives)
-		appendMultiArchValue(&buffer, "depends", pkg.Depends)
-		appendMultiArchValue(&buffer, "checkdepends", pkg.CheckDepends)
-		appendMultiArchValue(&buffer, "optdepends", pkg.OptDepends)
-		appendMultiArchValue(&buffer, "pacdeps", pkg.Pacdeps)
-		appendMultiArchValue(&buffer, "checkconflicts", pkg.CheckConflicts)
-		appendMultiArchValue(&buffer, "conflicts", pkg.Conflicts)
-		appendMultiArchValue(&buffer, "provides", pkg.Provides)
-		appendMultiArchValue(&buffer, "breaks", pkg.Breaks)
-		appendMultiArchValue(&buffer, "replaces", pkg.Replaces)
-		appendMultiArchValue(&buffer, "enhances", pkg.Enhances)
-		appendMultiArchValue(&buffer, "recommends", pkg.Recommends)
-		appendMultiArchValue(&buffer, "suggests", pkg.Suggests)
-		appendMultiValue(&buffer, "backup", pkg.Backup)
-		appendMultiValue(&buffer, "repology", pkg.Repology)
+		appendFields(&buffer, si, &si.Packages[n], ScopePackage)
 	}
 
 	return buffer.String()
@@ -55,3 +55,22 @@ func TestPrintSrcinfoEmpty(t *testing.T) {
 		t.Errorf("Empty srcinfo should generate empty string but gave: %s", str)
 	}
 }
+
+func TestPrintSrcinfoRoundTrip(t *testing.T) {
+	for _, name := range goodSrcinfos {
+		path := filepath.Join(goodSrcinfoDir, name)
+		srcinfo, err := ParseFile(path)
+		if err != nil {
+			t.Errorf("Error parsing %s: %s", name, err)
+			continue
+		}
+
+		file, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("Unable to read file: %s: %s", path, err.Error())
+			continue
+		}
+
+		srcinfosEqual(t, name, string(file), srcinfo.String())
+	}
+}
@@ -0,0 +1,181 @@
+package srcinfo
+
+import (
+	"slices"
+	"strings"
+)
+
+// Registry lists the distros, codenames and architectures that may be used as
+// suffixes of field names, such as depends_jammy or source_ubuntu_amd64.
+//
+// When a Registry is set in ParseOptions the suffix of each key is matched
+// against it instead of being guessed from the arch field. Keys are split
+// unambiguously into name_distro_arch where distro may itself be a distro, a
+// codename or distro_codename. Suffixes that are not known are reported as an
+// error.
+type Registry struct {
+	distros   map[string]struct{}
+	codenames map[string][]string // codename -> distros
+	arches    map[string]struct{}
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		distros:   make(map[string]struct{}),
+		codenames: make(map[string][]string),
+		arches:    make(map[string]struct{}),
+	}
+}
+
+// DefaultRegistry returns a new Registry containing the Debian and Ubuntu
+// releases as well as the architecture names used by dpkg and makepkg. The
+// returned Registry may be extended by the caller.
+func DefaultRegistry() *Registry {
+	reg := NewRegistry()
+
+	reg.AddDistro("debian", "buster", "bullseye", "bookworm", "trixie", "forky", "sid")
+	reg.AddDistro("ubuntu", "bionic", "focal", "jammy", "kinetic", "lunar", "mantic", "noble", "oracular", "plucky", "questing")
+	reg.AddArch("amd64", "arm64", "armel", "armhf", "i386", "mips64el", "ppc64el", "riscv64", "s390x")
+	reg.AddArch("x86_64", "aarch64", "i686", "armv7h", "armv6h", "pentium4", "loong64")
+
+	return reg
+}
+
+// AddDistro adds a distro and any number of its release codenames.
+func (reg *Registry) AddDistro(distro string, codenames ...string) {
+	reg.distros[distro] = struct{}{}
+
+	for _, codename := range codenames {
+		if !reg.hasCodename(distro, codename) {
+			reg.codenames[codename] = append(reg.codenames[codename], distro)
+		}
+	}
+}
+
+// AddArch adds architecture names.
+func (reg *Registry) AddArch(arches ...string) {
+	for _, arch := range arches {
+		reg.arches[arch] = struct{}{}
+	}
+}
+
+// IsDistro reports whether distro is a known distro.
+func (reg *Registry) IsDistro(distro string) bool {
+	_, ok := reg.distros[distro]
+	return ok
+}
+
+// IsArch reports whether arch is a known architecture.
+func (reg *Registry) IsArch(arch string) bool {
+	_, ok := reg.arches[arch]
+	return ok
+}
+
+// DistroOf returns the distro a codename belongs to. If the codename is used
+// by more than one distro the one added first is returned.
+func (reg *Registry) DistroOf(codename string) (string, bool) {
+	distros, ok := reg.codenames[codename]
+	if !ok {
+		return "", false
+	}
+
+	return distros[0], true
+}
+
+// clone returns a copy of the registry, nil staying nil.
+func (reg *Registry) clone() *Registry {
+	if reg == nil {
+		return nil
+	}
+
+	clone := NewRegistry()
+	for distro := range reg.distros {
+		clone.distros[distro] = struct{}{}
+	}
+
+	for codename, distros := range reg.codenames {
+		clone.codenames[codename] = slices.Clone(distros)
+	}
+
+	for arch := range reg.arches {
+		clone.arches[arch] = struct{}{}
+	}
+
+	return clone
+}
+
+func (reg *Registry) hasCodename(distro, codename string) bool {
+	for _, d := range reg.codenames[codename] {
+		if d == distro {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDistroSuffix reports whether s names a distro, a codename or a codename of
+// a distro in the form distro_codename.
+func (reg *Registry) isDistroSuffix(s string) bool {
+	if reg.IsDistro(s) {
+		return true
+	}
+
+	if _, ok := reg.codenames[s]; ok {
+		return true
+	}
+
+	for distro := range reg.distros {
+		if codename, ok := strings.CutPrefix(s, distro+"_"); ok && reg.hasCodename(distro, codename) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitKey splits a key in the form name[_distro][_arch]. arches are the
+// architectures declared by the srcinfo, which are accepted along with those
+// in the registry and their aliases.
+func (reg *Registry) splitKey(aliases *ArchAliases, arches []string, key string) (string, string, string, error) {
+	name, suffix, ok := strings.Cut(key, "_")
+	if !ok {
+		return key, "", "", nil
+	}
+
+	isArch := func(arch string) bool {
+		if aliases.isAny(arch) || reg.IsArch(arch) {
+			return true
+		}
+
+		for _, a := range arches {
+			if aliases.Equal(a, arch) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// find the longest trailing arch, so that name_x86_64 is never read as
+	// the distro x86 with the arch 64
+	arch, distro := "", suffix
+	for n := 0; n < len(suffix); n++ {
+		if n != 0 && suffix[n-1] != '_' {
+			continue
+		}
+
+		if isArch(suffix[n:]) {
+			arch = suffix[n:]
+			distro = strings.TrimSuffix(suffix[:n], "_")
+			break
+		}
+	}
+
+	if distro != "" && !reg.isDistroSuffix(distro) {
+		return "", "", "", keyErrorf(ErrUnknownSuffix, key, "Invalid key \"%s\" unknown distro or arch \"%s\"", key, distro)
+	}
+
+	return name, distro, arch, nil
+}
@@ -0,0 +1,77 @@
+package srcinfo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistrySplitKey(t *testing.T) {
+	reg := DefaultRegistry()
+	reg.AddDistro("pop", "jammy")
+	arches := []string{"amd64", "x86_64", "foo_bar"}
+
+	tests := []struct {
+		key    string
+		name   string
+		distro string
+		arch   string
+	}{
+		{"depends", "depends", "", ""},
+		{"depends_amd64", "depends", "", "amd64"},
+		{"depends_x86_64", "depends", "", "x86_64"},
+		{"depends_foo_bar", "depends", "", "foo_bar"},
+		{"depends_jammy", "depends", "jammy", ""},
+		{"depends_ubuntu", "depends", "ubuntu", ""},
+		{"depends_ubuntu_jammy", "depends", "ubuntu_jammy", ""},
+		{"depends_pop_jammy", "depends", "pop_jammy", ""},
+		{"depends_jammy_x86_64", "depends", "jammy", "x86_64"},
+		{"depends_debian_bookworm_amd64", "depends", "debian_bookworm", "amd64"},
+		{"source_debian_any", "source", "debian", "any"},
+	}
+
+	for _, test := range tests {
+		name, distro, arch, err := reg.splitKey(nil, arches, test.key)
+		if err != nil {
+			t.Errorf("%s: %s", test.key, err)
+			continue
+		}
+
+		if name != test.name || distro != test.distro || arch != test.arch {
+			t.Errorf("%s: expected name=%s distro=%s arch=%s, got name=%s distro=%s arch=%s",
+				test.key, test.name, test.distro, test.arch, name, distro, arch)
+		}
+	}
+
+	bad := []string{
+		"depends_amd46",
+		"depends_debian_jammy",
+		"depends_jamy_amd64",
+		"depends_x86",
+	}
+
+	for _, key := range bad {
+		_, _, _, err := reg.splitKey(nil, arches, key)
+		if !errors.Is(err, ErrUnknownSuffix) {
+			t.Errorf("%s: error should be %v but was %v", key, ErrUnknownSuffix, err)
+		}
+	}
+}
+
+func TestRegistryParse(t *testing.T) {
+	data := "pkgbase = foo\n\tpkgver = 1\n\tarch = amd64\n\tdepends_ubuntu_jammy_amd64 = bar\n\npkgname = foo\n"
+
+	srcinfo, err := ParseWithOptions(data, ParseOptions{Registry: DefaultRegistry()})
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	expected := ArchDistroString{"amd64", "ubuntu_jammy", "bar"}
+	if len(srcinfo.Depends) != 1 || srcinfo.Depends[0] != expected {
+		t.Errorf("depends should be %#v but was %#v", expected, srcinfo.Depends)
+	}
+
+	_, err = ParseWithOptions(data, ParseOptions{Registry: NewRegistry()})
+	if !errors.Is(err, ErrUnknownSuffix) {
+		t.Errorf("error should be %v but was %v", ErrUnknownSuffix, err)
+	}
+}
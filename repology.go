@@ -0,0 +1,182 @@
+package srcinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// RepologyPackage is a package of a repology project, as returned by the
+// repology API.
+type RepologyPackage struct {
+	Repo        string   `json:"repo"`
+	Subrepo     string   `json:"subrepo,omitempty"`
+	SrcName     string   `json:"srcname,omitempty"`
+	BinName     string   `json:"binname,omitempty"`
+	BinNames    []string `json:"binnames,omitempty"`
+	VisibleName string   `json:"visiblename,omitempty"`
+	Version     string   `json:"version"`
+	OrigVersion string   `json:"origversion,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Categories  []string `json:"categories,omitempty"`
+	Licenses    []string `json:"licenses,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+}
+
+// RepologyDump maps the name of each repology project to its packages, in the
+// same form as the projects endpoint of the repology API.
+type RepologyDump map[string][]RepologyPackage
+
+// ReadRepologyDump reads a RepologyDump in JSON form.
+func ReadRepologyDump(r io.Reader) (RepologyDump, error) {
+	var dump RepologyDump
+
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return nil, fmt.Errorf("Invalid repology dump: %s", err.Error())
+	}
+
+	return dump, nil
+}
+
+// LoadRepologyDump reads a RepologyDump from a JSON file as specified by path.
+func LoadRepologyDump(path string) (RepologyDump, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read file: %s: %s", path, err.Error())
+	}
+	defer file.Close()
+
+	return ReadRepologyDump(file)
+}
+
+// RepologyFilter is the parsed repology field of a package. Project names the
+// repology project and every other non-empty field must equal the field of the
+// same name of a RepologyPackage for it to match.
+type RepologyFilter struct {
+	Project     string
+	Repo        string
+	Subrepo     string
+	SrcName     string
+	BinName     string
+	VisibleName string
+	Version     string
+	OrigVersion string
+	Status      string
+	Summary     string
+}
+
+// repologyKey is a key of the repology field other than project.
+type repologyKey struct {
+	filter func(*RepologyFilter) *string
+	pkg    func(*RepologyPackage) string
+}
+
+var repologyKeys = map[string]repologyKey{
+	"repo":        {func(f *RepologyFilter) *string { return &f.Repo }, func(p *RepologyPackage) string { return p.Repo }},
+	"subrepo":     {func(f *RepologyFilter) *string { return &f.Subrepo }, func(p *RepologyPackage) string { return p.Subrepo }},
+	"srcname":     {func(f *RepologyFilter) *string { return &f.SrcName }, func(p *RepologyPackage) string { return p.SrcName }},
+	"binname":     {func(f *RepologyFilter) *string { return &f.BinName }, func(p *RepologyPackage) string { return p.BinName }},
+	"visiblename": {func(f *RepologyFilter) *string { return &f.VisibleName }, func(p *RepologyPackage) string { return p.VisibleName }},
+	"version":     {func(f *RepologyFilter) *string { return &f.Version }, func(p *RepologyPackage) string { return p.Version }},
+	"origversion": {func(f *RepologyFilter) *string { return &f.OrigVersion }, func(p *RepologyPackage) string { return p.OrigVersion }},
+	"status":      {func(f *RepologyFilter) *string { return &f.Status }, func(p *RepologyPackage) string { return p.Status }},
+	"summary":     {func(f *RepologyFilter) *string { return &f.Summary }, func(p *RepologyPackage) string { return p.Summary }},
+}
+
+// ParseRepology parses the lines of a repology field in the form
+// "key: value". The project key is required, the other known keys are the
+// string fields of RepologyPackage. Each key may only be given once.
+func ParseRepology(lines []string) (RepologyFilter, error) {
+	var f RepologyFilter
+	seen := make(map[string]struct{})
+
+	for _, line := range lines {
+		if line == EmptyOverride {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if !ok || key == "" || value == "" {
+			return RepologyFilter{}, fmt.Errorf("Invalid repology line \"%s\": expected \"key: value\"", line)
+		}
+
+		if _, ok := seen[key]; ok {
+			return RepologyFilter{}, fmt.Errorf("Invalid repology line \"%s\": key \"%s\" can not occur more than once", line, key)
+		}
+		seen[key] = struct{}{}
+
+		if key == "project" {
+			f.Project = value
+			continue
+		}
+
+		rk, ok := repologyKeys[key]
+		if !ok {
+			return RepologyFilter{}, fmt.Errorf("Invalid repology line \"%s\": unknown key \"%s\"", line, key)
+		}
+
+		*rk.filter(&f) = value
+	}
+
+	if f.Project == "" {
+		return RepologyFilter{}, fmt.Errorf("Invalid repology field: no project")
+	}
+
+	return f, nil
+}
+
+// ParsedRepology returns the repology field of the package as a
+// RepologyFilter, see ParseRepology.
+func (pkg *Package) ParsedRepology() (RepologyFilter, error) {
+	return ParseRepology(pkg.Repology)
+}
+
+// Lines formats the filter as the lines of a repology field, the project
+// first and then every other non-empty key in alphabetical order.
+func (f RepologyFilter) Lines() []string {
+	lines := []string{"project: " + f.Project}
+
+	for _, key := range []string{"binname", "origversion", "repo", "srcname", "status", "subrepo", "summary", "version", "visiblename"} {
+		if value := *repologyKeys[key].filter(&f); value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+
+	return lines
+}
+
+// Matches reports whether every key of the filter other than project equals
+// the matching field of pkg.
+func (f RepologyFilter) Matches(pkg RepologyPackage) bool {
+	for _, rk := range repologyKeys {
+		if value := *rk.filter(&f); value != "" && value != rk.pkg(&pkg) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Match returns the packages of the filter's project in dump that match the
+// filter. An error is returned if the project is not part of the dump.
+func (f RepologyFilter) Match(dump RepologyDump) ([]RepologyPackage, error) {
+	pkgs, ok := dump[f.Project]
+	if !ok {
+		return nil, fmt.Errorf("Repology project \"%s\" is not part of the dump", f.Project)
+	}
+
+	var matched []RepologyPackage
+	for _, pkg := range pkgs {
+		if f.Matches(pkg) {
+			matched = append(matched, pkg)
+		}
+	}
+
+	return matched, nil
+}
@@ -0,0 +1,89 @@
+package srcinfo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRepology(t *testing.T) {
+	lines := []string{"project: firefox", "repo: arch", "visiblename:firefox", EmptyOverride}
+
+	f, err := ParseRepology(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RepologyFilter{Project: "firefox", Repo: "arch", VisibleName: "firefox"}
+	if f != expected {
+		t.Errorf("expected %#v got %#v", expected, f)
+	}
+
+	if !reflect.DeepEqual(f.Lines(), []string{"project: firefox", "repo: arch", "visiblename: firefox"}) {
+		t.Errorf("unexpected lines: %v", f.Lines())
+	}
+
+	bad := [][]string{
+		nil,
+		{"repo: arch"},
+		{"project firefox"},
+		{"project: "},
+		{"project: firefox", "project: firefox-esr"},
+		{"project: firefox", "color: blue"},
+	}
+
+	for _, lines := range bad {
+		if _, err := ParseRepology(lines); err == nil {
+			t.Errorf("%v: should have errored", lines)
+		}
+	}
+}
+
+func TestRepologyMatch(t *testing.T) {
+	dump, err := LoadRepologyDump("testdata/repology/projects.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcinfo, err := Parse(`pkgbase = firefox-bin
+	pkgver = 131.0.3
+	repology = project: firefox
+	repology = status: newest
+pkgname = firefox-bin`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := srcinfo.Package.ParsedRepology()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgs, err := f.Match(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pkgs) != 1 || pkgs[0].Repo != "arch" || pkgs[0].Version != "131.0.3" {
+		t.Errorf("unexpected packages: %#v", pkgs)
+	}
+
+	f = RepologyFilter{Project: "firefox", Status: "legacy"}
+	if pkgs, _ := f.Match(dump); len(pkgs) != 2 {
+		t.Errorf("expected 2 packages got %#v", pkgs)
+	}
+
+	f = RepologyFilter{Project: "firefox", Repo: "fedora_rawhide"}
+	if pkgs, err := f.Match(dump); err != nil || len(pkgs) != 0 {
+		t.Errorf("expected no packages got %#v: %v", pkgs, err)
+	}
+
+	f = RepologyFilter{Project: "chromium"}
+	if _, err := f.Match(dump); err == nil {
+		t.Error("missing project should have errored")
+	}
+
+	if _, err := ReadRepologyDump(strings.NewReader("[]")); err == nil {
+		t.Error("invalid dump should have errored")
+	}
+}
@@ -0,0 +1,218 @@
+package srcinfo
+
+import (
+	"slices"
+)
+
+// Target is a system a srcinfo may be installed on.
+type Target struct {
+	Arch     string // Architecture, such as amd64
+	Distro   string // Distro name, such as ubuntu
+	Codename string // Release codename, such as jammy
+}
+
+// ResolvedPackage is a package as it applies to a single Target. Every arch
+// and distro dependent field has been reduced to the values that apply, see
+// Target.Values. Fields of the package base are included so that each
+// ResolvedPackage is complete on its own.
+type ResolvedPackage struct {
+	Pkgbase  string
+	Pkgname  string
+	Version  string
+	Pkgdesc  string
+	URL      string
+	Priority string
+	Arch     []string
+	License  []string
+
+	Gives          []string
+	Depends        []string
+	CheckDepends   []string
+	MakeDepends    []string
+	OptDepends     []string
+	Pacdeps        []string
+	CheckConflicts []string
+	MakeConflicts  []string
+	Conflicts      []string
+	Provides       []string
+	Breaks         []string
+	Replaces       []string
+	Enhances       []string
+	Recommends     []string
+	Suggests       []string
+
+	Source     []string
+	MD5Sums    []string
+	SHA1Sums   []string
+	SHA224Sums []string
+	SHA256Sums []string
+	SHA384Sums []string
+	SHA512Sums []string
+	B2Sums     []string
+
+	Backup   []string
+	Repology []string
+	Extra    map[string][]string // Extra fields by key
+}
+
+// distroLevel returns how specifically distro matches the target: 0 if
+// distro is empty, 1 for the distro name and 2 for the codename, either alone
+// or as distro_codename. -1 is returned if distro does not match.
+func (t Target) distroLevel(distro string) int {
+	switch {
+	case distro == "":
+		return 0
+	case t.Distro != "" && distro == t.Distro:
+		return 1
+	case t.Codename != "" && (distro == t.Codename || (t.Distro != "" && distro == t.Distro+"_"+t.Codename)):
+		return 2
+	default:
+		return -1
+	}
+}
+
+// Values returns the values that apply to the target. Each value belongs to
+// one of these levels, from least to most specific:
+//
+//	generic       depends
+//	distro        depends_ubuntu
+//	codename      depends_jammy or depends_ubuntu_jammy
+//	arch          depends_amd64
+//	distro+arch   depends_ubuntu_amd64, depends_jammy_amd64 or depends_ubuntu_jammy_amd64
+//
+// Of the generic, distro and codename levels only the most specific level
+// with values for the target is used, so depends_jammy replaces depends on
+// jammy. Likewise only the most specific of the arch levels is used, so
+// depends_jammy_amd64 replaces depends_amd64 on jammy. The arch values are
+// then appended to the others, as an arch specific field adds to the generic
+// one.
+//
+// Empty overrides are skipped, see EmptyOverride. Arches are matched using
+// the built in aliases, use ValuesWith for others.
+func (t Target) Values(values []ArchDistroString) []string {
+	return t.ValuesWith(nil, values)
+}
+
+// ValuesWith is Values, matching arches using aliases. A nil aliases uses the
+// built in aliases.
+func (t Target) ValuesWith(aliases *ArchAliases, values []ArchDistroString) []string {
+	var levels [2][3][]ArchDistroString
+
+	for _, v := range values {
+		level := t.distroLevel(v.Distro)
+		if level == -1 || v.Value == EmptyOverride || (v.Arch != "" && !aliases.Equal(v.Arch, t.Arch)) {
+			continue
+		}
+
+		arch := 0
+		if v.Arch != "" {
+			arch = 1
+		}
+
+		levels[arch][level] = append(levels[arch][level], v)
+	}
+
+	resolved := []string{}
+
+	for arch := range levels {
+		for level := 2; level >= 0; level-- {
+			if len(levels[arch][level]) == 0 {
+				continue
+			}
+
+			for _, v := range levels[arch][level] {
+				resolved = append(resolved, v.Value)
+			}
+
+			break
+		}
+	}
+
+	return resolved
+}
+
+// supports reports whether a package built for arches may be installed on
+// the target. Every arch is supported if the target has none.
+func (t Target) supports(aliases *ArchAliases, arches []string) bool {
+	if t.Arch == "" {
+		return true
+	}
+
+	for _, arch := range arches {
+		if aliases.Equal(arch, t.Arch) || aliases.isAny(arch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns every package of the srcinfo as it applies to the target,
+// after merging each split package with the package base. Packages whose
+// arch does not include the target's arch or "any" are left out.
+//
+// Fields are resolved using Target.ValuesWith and the ArchAliases the srcinfo
+// was parsed with, if any. The returned
+// packages share no slices with the srcinfo.
+func (si *Srcinfo) Resolve(t Target) []ResolvedPackage {
+	resolved := make([]ResolvedPackage, 0, len(si.Packages))
+
+	for _, pkg := range si.SplitPackages() {
+		if !t.supports(si.aliases, pkg.Arch) {
+			continue
+		}
+
+		extra := make(map[string][]ArchDistroString)
+		for _, v := range pkg.Extra {
+			extra[v.Key] = append(extra[v.Key], v.ArchDistroString)
+		}
+
+		rp := ResolvedPackage{
+			Pkgbase:  si.Pkgbase,
+			Pkgname:  pkg.Pkgname,
+			Version:  si.Version(),
+			Pkgdesc:  pkg.Pkgdesc,
+			URL:      pkg.URL,
+			Priority: pkg.Priority,
+			Arch:     slices.Clone(pkg.Arch),
+			License:  slices.Clone(pkg.License),
+
+			Gives:          t.ValuesWith(si.aliases, pkg.Gives),
+			Depends:        t.ValuesWith(si.aliases, pkg.Depends),
+			CheckDepends:   t.ValuesWith(si.aliases, pkg.CheckDepends),
+			MakeDepends:    t.ValuesWith(si.aliases, si.MakeDepends),
+			OptDepends:     t.ValuesWith(si.aliases, pkg.OptDepends),
+			Pacdeps:        t.ValuesWith(si.aliases, pkg.Pacdeps),
+			CheckConflicts: t.ValuesWith(si.aliases, pkg.CheckConflicts),
+			MakeConflicts:  t.ValuesWith(si.aliases, si.MakeConflicts),
+			Conflicts:      t.ValuesWith(si.aliases, pkg.Conflicts),
+			Provides:       t.ValuesWith(si.aliases, pkg.Provides),
+			Breaks:         t.ValuesWith(si.aliases, pkg.Breaks),
+			Replaces:       t.ValuesWith(si.aliases, pkg.Replaces),
+			Enhances:       t.ValuesWith(si.aliases, pkg.Enhances),
+			Recommends:     t.ValuesWith(si.aliases, pkg.Recommends),
+			Suggests:       t.ValuesWith(si.aliases, pkg.Suggests),
+
+			Source:     t.ValuesWith(si.aliases, si.Source),
+			MD5Sums:    t.ValuesWith(si.aliases, si.MD5Sums),
+			SHA1Sums:   t.ValuesWith(si.aliases, si.SHA1Sums),
+			SHA224Sums: t.ValuesWith(si.aliases, si.SHA224Sums),
+			SHA256Sums: t.ValuesWith(si.aliases, si.SHA256Sums),
+			SHA384Sums: t.ValuesWith(si.aliases, si.SHA384Sums),
+			SHA512Sums: t.ValuesWith(si.aliases, si.SHA512Sums),
+			B2Sums:     t.ValuesWith(si.aliases, si.B2Sums),
+
+			Backup:   slices.Clone(pkg.Backup),
+			Repology: slices.Clone(pkg.Repology),
+			Extra:    make(map[string][]string, len(extra)),
+		}
+
+		for key, values := range extra {
+			rp.Extra[key] = t.ValuesWith(si.aliases, values)
+		}
+
+		resolved = append(resolved, rp)
+	}
+
+	return resolved
+}
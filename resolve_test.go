@@ -0,0 +1,110 @@
+package srcinfo
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTargetValues(t *testing.T) {
+	values := []ArchDistroString{
+		{"", "", "generic"},
+		{"", "ubuntu", "distro"},
+		{"", "jammy", "codename"},
+		{"", "ubuntu_jammy", "distro_codename"},
+		{"amd64", "", "arch"},
+		{"amd64", "ubuntu", "distro_arch"},
+		{"amd64", "jammy", "codename_arch"},
+		{"arm64", "", "other_arch"},
+		{"", "debian", "other_distro"},
+	}
+
+	tests := []struct {
+		target   Target
+		expected []string
+	}{
+		{Target{}, []string{"generic"}},
+		{Target{Arch: "amd64"}, []string{"generic", "arch"}},
+		{Target{Distro: "ubuntu"}, []string{"distro"}},
+		{Target{Distro: "ubuntu", Codename: "noble"}, []string{"distro"}},
+		{Target{Distro: "ubuntu", Codename: "jammy"}, []string{"codename", "distro_codename"}},
+		{Target{"amd64", "ubuntu", "noble"}, []string{"distro", "distro_arch"}},
+		{Target{"amd64", "ubuntu", "jammy"}, []string{"codename", "distro_codename", "codename_arch"}},
+		{Target{"amd64", "debian", "bookworm"}, []string{"other_distro", "arch"}},
+		{Target{"arm64", "fedora", "40"}, []string{"generic", "other_arch"}},
+		{Target{"i386", "", ""}, []string{"generic"}},
+	}
+
+	for _, test := range tests {
+		resolved := test.target.Values(values)
+		if !reflect.DeepEqual(resolved, test.expected) {
+			t.Errorf("%#v: expected %v got %v", test.target, test.expected, resolved)
+		}
+	}
+
+	skipped := []ArchDistroString{{"", "", "a"}, {"", "jammy", EmptyOverride}, {"amd64", "", "b"}}
+	if resolved := (Target{"amd64", "ubuntu", "jammy"}).Values(skipped); !reflect.DeepEqual(resolved, []string{"a", "b"}) {
+		t.Errorf("empty override should be skipped, got %v", resolved)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	srcinfo, err := ParseFile(filepath.Join(goodSrcinfoDir, "distro_override"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved := srcinfo.Resolve(Target{"x86_64", "ubuntu", "jammy"})
+	if len(resolved) != 3 {
+		t.Fatalf("expected 3 packages got %d", len(resolved))
+	}
+
+	expected := map[string][]string{
+		"foo": {"g", "e"},
+		"bar": {"h", "i"},
+		"baz": {"b", "j"},
+	}
+
+	for _, pkg := range resolved {
+		if !reflect.DeepEqual(pkg.Depends, expected[pkg.Pkgname]) {
+			t.Errorf("%s: expected depends %v got %v", pkg.Pkgname, expected[pkg.Pkgname], pkg.Depends)
+		}
+
+		if pkg.Pkgbase != "distro_override" || pkg.Version != "1-1" {
+			t.Errorf("%s: package base fields were not filled in: %#v", pkg.Pkgname, pkg)
+		}
+	}
+
+	resolved = srcinfo.Resolve(Target{"aarch64", "debian", "bookworm"})
+	expected = map[string][]string{
+		"foo": {"g"},
+		"bar": {"c"},
+		// baz removes depends_bookworm, so the generic depends apply
+		"baz": {"a"},
+	}
+
+	for _, pkg := range resolved {
+		if !reflect.DeepEqual(pkg.Depends, expected[pkg.Pkgname]) {
+			t.Errorf("%s: expected depends %v got %v", pkg.Pkgname, expected[pkg.Pkgname], pkg.Depends)
+		}
+	}
+
+	if resolved := srcinfo.Resolve(Target{Arch: "i686"}); len(resolved) != 0 {
+		t.Errorf("packages for other arches should be left out: %#v", resolved)
+	}
+
+	srcinfo, err = Parse(srcinfoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved = srcinfo.Resolve(Target{Arch: "x86_64"})
+	if len(resolved) != 2 || len(resolved[0].Source) != len(srcinfo.Source) || resolved[0].Extra["install"][0] != "linux.install" {
+		t.Errorf("unexpected resolved packages: %#v", resolved)
+	}
+	resolved[0].Arch[0] = "foo"
+	resolved[0].License[0] = "foo"
+	if srcinfo.Arch[0] == "foo" || srcinfo.License[0] == "foo" {
+		t.Errorf("resolved packages should not share slices with the srcinfo")
+	}
+}
@@ -0,0 +1,241 @@
+package srcinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope describes which sections of a srcinfo a field may appear in.
+type Scope int
+
+const (
+	// ScopeBase fields may only appear in the pkgbase section.
+	ScopeBase Scope = iota
+	// ScopePackage fields may appear in the pkgbase section and be
+	// overridden in each pkgname section.
+	ScopePackage
+)
+
+// Field describes a key that may appear in a srcinfo.
+//
+// Fields that are built in are stored in the matching field of PackageBase or
+// Package. Fields registered by the user are stored in Package.Extra.
+type Field struct {
+	Name       string // Key without any arch or distro suffix
+	Scope      Scope  // Sections the field may appear in
+	Multi      bool   // Every value of a repeated key is kept, not only the last
+	ArchDistro bool   // The key may have an arch or distro suffix
+
+	// Accessors for built in fields, only one is set. Fields with ScopePackage
+	// ignore the Srcinfo and may be called with nil.
+	str  func(*Srcinfo, *Package) *string
+	strs func(*Srcinfo, *Package) *[]string
+	ads  func(*Srcinfo, *Package) *[]ArchDistroString
+}
+
+// IsBuiltin reports whether the field is stored in a field of PackageBase or
+// Package rather than in Package.Extra.
+func (f *Field) IsBuiltin() bool {
+	return f.str != nil || f.strs != nil || f.ads != nil
+}
+
+// set stores a parsed value. pkg is the package of the section the value was
+// found in.
+func (f *Field) set(si *Srcinfo, pkg *Package, value ArchDistroString) {
+	switch {
+	case f.str != nil:
+		*f.str(si, pkg) = value.Value
+	case f.strs != nil:
+		strs := f.strs(si, pkg)
+		*strs = append(*strs, value.Value)
+	case f.ads != nil:
+		ads := f.ads(si, pkg)
+		*ads = append(*ads, value)
+	default:
+		if !f.Multi {
+			pkg.Extra = removeExtra(pkg.Extra, f.Name, value.Arch, value.Distro)
+		}
+
+		pkg.Extra = append(pkg.Extra, ExtraField{f.Name, value})
+	}
+}
+
+func removeExtra(extra []ExtraField, key, arch, distro string) []ExtraField {
+	kept := extra[:0]
+
+	for _, v := range extra {
+		if v.Key != key || v.Arch != arch || v.Distro != distro {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
+
+func baseString(name string, get func(*PackageBase) *string) *Field {
+	return &Field{Name: name, Scope: ScopeBase,
+		str: func(si *Srcinfo, _ *Package) *string { return get(&si.PackageBase) }}
+}
+
+func baseStrings(name string, get func(*PackageBase) *[]string) *Field {
+	return &Field{Name: name, Scope: ScopeBase, Multi: true,
+		strs: func(si *Srcinfo, _ *Package) *[]string { return get(&si.PackageBase) }}
+}
+
+func baseArchStrings(name string, get func(*PackageBase) *[]ArchDistroString) *Field {
+	return &Field{Name: name, Scope: ScopeBase, Multi: true, ArchDistro: true,
+		ads: func(si *Srcinfo, _ *Package) *[]ArchDistroString { return get(&si.PackageBase) }}
+}
+
+func packageString(name string, get func(*Package) *string) *Field {
+	return &Field{Name: name, Scope: ScopePackage,
+		str: func(_ *Srcinfo, pkg *Package) *string { return get(pkg) }}
+}
+
+func packageStrings(name string, get func(*Package) *[]string) *Field {
+	return &Field{Name: name, Scope: ScopePackage, Multi: true,
+		strs: func(_ *Srcinfo, pkg *Package) *[]string { return get(pkg) }}
+}
+
+func packageArchStrings(name string, get func(*Package) *[]ArchDistroString) *Field {
+	return &Field{Name: name, Scope: ScopePackage, Multi: true, ArchDistro: true,
+		ads: func(_ *Srcinfo, pkg *Package) *[]ArchDistroString { return get(pkg) }}
+}
+
+// builtinFields are all the fields with a field of their own in PackageBase or
+// Package, in the order they are printed by String.
+var builtinFields = []*Field{
+	packageString("pkgdesc", func(p *Package) *string { return &p.Pkgdesc }),
+	baseString("pkgver", func(b *PackageBase) *string { return &b.Pkgver }),
+	baseString("pkgrel", func(b *PackageBase) *string { return &b.Pkgrel }),
+	baseString("epoch", func(b *PackageBase) *string { return &b.Epoch }),
+	packageString("url", func(p *Package) *string { return &p.URL }),
+	packageString("priority", func(p *Package) *string { return &p.Priority }),
+	packageStrings("arch", func(p *Package) *[]string { return &p.Arch }),
+	packageStrings("license", func(p *Package) *[]string { return &p.License }),
+	packageArchStrings("gives", func(p *Package) *[]ArchDistroString { return &p.Gives }),
+	packageArchStrings("depends", func(p *Package) *[]ArchDistroString { return &p.Depends }),
+	packageArchStrings("checkdepends", func(p *Package) *[]ArchDistroString { return &p.CheckDepends }),
+	baseArchStrings("makedepends", func(b *PackageBase) *[]ArchDistroString { return &b.MakeDepends }),
+	packageArchStrings("optdepends", func(p *Package) *[]ArchDistroString { return &p.OptDepends }),
+	packageArchStrings("pacdeps", func(p *Package) *[]ArchDistroString { return &p.Pacdeps }),
+	packageArchStrings("checkconflicts", func(p *Package) *[]ArchDistroString { return &p.CheckConflicts }),
+	baseArchStrings("makeconflicts", func(b *PackageBase) *[]ArchDistroString { return &b.MakeConflicts }),
+	packageArchStrings("conflicts", func(p *Package) *[]ArchDistroString { return &p.Conflicts }),
+	packageArchStrings("provides", func(p *Package) *[]ArchDistroString { return &p.Provides }),
+	packageArchStrings("breaks", func(p *Package) *[]ArchDistroString { return &p.Breaks }),
+	packageArchStrings("replaces", func(p *Package) *[]ArchDistroString { return &p.Replaces }),
+	packageArchStrings("enhances", func(p *Package) *[]ArchDistroString { return &p.Enhances }),
+	packageArchStrings("recommends", func(p *Package) *[]ArchDistroString { return &p.Recommends }),
+	packageArchStrings("suggests", func(p *Package) *[]ArchDistroString { return &p.Suggests }),
+	baseStrings("mask", func(b *PackageBase) *[]string { return &b.Mask }),
+	baseStrings("compatible", func(b *PackageBase) *[]string { return &b.Compatible }),
+	baseStrings("incompatible", func(b *PackageBase) *[]string { return &b.Incompatible }),
+	baseStrings("maintainer", func(b *PackageBase) *[]string { return &b.Maintainer }),
+	baseArchStrings("source", func(b *PackageBase) *[]ArchDistroString { return &b.Source }),
+	baseStrings("noextract", func(b *PackageBase) *[]string { return &b.NoExtract }),
+	baseStrings("nosubmodules", func(b *PackageBase) *[]string { return &b.NoSubmodules }),
+	baseArchStrings("md5sums", func(b *PackageBase) *[]ArchDistroString { return &b.MD5Sums }),
+	baseArchStrings("sha1sums", func(b *PackageBase) *[]ArchDistroString { return &b.SHA1Sums }),
+	baseArchStrings("sha224sums", func(b *PackageBase) *[]ArchDistroString { return &b.SHA224Sums }),
+	baseArchStrings("sha256sums", func(b *PackageBase) *[]ArchDistroString { return &b.SHA256Sums }),
+	baseArchStrings("sha384sums", func(b *PackageBase) *[]ArchDistroString { return &b.SHA384Sums }),
+	baseArchStrings("sha512sums", func(b *PackageBase) *[]ArchDistroString { return &b.SHA512Sums }),
+	baseArchStrings("b2sums", func(b *PackageBase) *[]ArchDistroString { return &b.B2Sums }),
+	packageStrings("backup", func(p *Package) *[]string { return &p.Backup }),
+	packageStrings("repology", func(p *Package) *[]string { return &p.Repology }),
+}
+
+// Schema is an ordered set of the fields that are known when parsing. Each
+// Dialect has its own Schema.
+//
+// The schemas of the built in dialects are shared and read only, Register
+// returns an error for them. Use Clone to get a Schema that fields may be
+// registered with.
+type Schema struct {
+	fields   []*Field
+	index    map[string]*Field
+	readOnly bool
+}
+
+// NewSchema returns a Schema containing the named built in fields. Names that
+// are not built in are ignored.
+func NewSchema(names ...string) *Schema {
+	schema := &Schema{index: make(map[string]*Field)}
+
+	for _, name := range names {
+		for _, field := range builtinFields {
+			if field.Name == name {
+				schema.add(field)
+			}
+		}
+	}
+
+	return schema
+}
+
+func (s *Schema) add(field *Field) {
+	s.fields = append(s.fields, field)
+	s.index[field.Name] = field
+}
+
+// Clone returns a copy of the Schema that may be modified independently.
+func (s *Schema) Clone() *Schema {
+	clone := &Schema{index: make(map[string]*Field, len(s.index))}
+
+	for _, field := range s.fields {
+		clone.add(field)
+	}
+
+	return clone
+}
+
+// Register adds a field to the Schema. Registered fields are stored in
+// Package.Extra, are merged by SplitPackage and printed by String the same way
+// as unknown fields, but are not an error when parsing strictly. A field is
+// only accepted in the sections allowed by Scope and with a suffix if
+// ArchDistro is set. Like the built in fields, a repeated key that is not
+// Multi replaces the earlier value.
+func (s *Schema) Register(field Field) error {
+	if s.readOnly {
+		return fmt.Errorf("Schema is read only, use Clone to register \"%s\"", field.Name)
+	}
+
+	if field.Name == "" || strings.ContainsAny(field.Name, "_= \t") {
+		return fmt.Errorf("Invalid field name \"%s\"", field.Name)
+	}
+
+	if _, ok := s.index[field.Name]; ok || field.Name == "pkgbase" || field.Name == "pkgname" {
+		return fmt.Errorf("Field \"%s\" is already registered", field.Name)
+	}
+
+	s.add(&Field{
+		Name:       field.Name,
+		Scope:      field.Scope,
+		Multi:      field.Multi,
+		ArchDistro: field.ArchDistro,
+	})
+
+	return nil
+}
+
+// Lookup returns the field for a key without any arch or distro suffix.
+func (s *Schema) Lookup(name string) (Field, bool) {
+	field, ok := s.index[name]
+	if !ok {
+		return Field{}, false
+	}
+
+	return *field, true
+}
+
+// Fields returns every field in the Schema in the order they were added.
+func (s *Schema) Fields() []Field {
+	fields := make([]Field, 0, len(s.fields))
+
+	for _, field := range s.fields {
+		fields = append(fields, *field)
+	}
+
+	return fields
+}
@@ -0,0 +1,117 @@
+package srcinfo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaRegister(t *testing.T) {
+	schema := PacstallDialect.Schema.Clone()
+
+	if err := schema.Register(Field{Name: "homepage", Scope: ScopePackage}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schema.Register(Field{Name: "tags", Scope: ScopePackage, Multi: true, ArchDistro: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schema.Register(Field{Name: "signer", Scope: ScopeBase, Multi: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", "depends", "pkgname", "foo_bar"} {
+		if err := schema.Register(Field{Name: name}); err == nil {
+			t.Errorf("registering \"%s\" should have errored", name)
+		}
+	}
+
+	for _, dialect := range []*Dialect{PacstallDialect, ArchDialect} {
+		if err := dialect.Schema.Register(Field{Name: "homepage"}); err == nil {
+			t.Errorf("registering on the %s schema should have errored", dialect.Name)
+		}
+	}
+
+	if PacstallDialect.IsKnown("homepage") {
+		t.Errorf("registering on a clone should not change the original schema")
+	}
+
+	field, ok := schema.Lookup("tags")
+	if !ok || field.IsBuiltin() || !field.Multi || !field.ArchDistro {
+		t.Errorf("unexpected field: %#v", field)
+	}
+
+	dialect := *PacstallDialect
+	dialect.Schema = schema
+	dialect.Strict = true
+
+	data := `pkgbase = foo
+	pkgver = 1
+	homepage = a
+	homepage = b
+	tags = x
+	tags_jammy = y
+	signer = me
+
+pkgname = foo
+	tags = z
+`
+
+	srcinfo, err := ParseWithOptions(data, ParseOptions{Dialect: &dialect})
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	expected := []ExtraField{
+		{Key: "homepage", ArchDistroString: ArchDistroString{Value: "b"}},
+		{Key: "tags", ArchDistroString: ArchDistroString{Value: "x"}},
+		{Key: "tags", ArchDistroString: ArchDistroString{Distro: "jammy", Value: "y"}},
+		{Key: "signer", ArchDistroString: ArchDistroString{Value: "me"}},
+	}
+	if !reflect.DeepEqual(srcinfo.Extra, expected) {
+		t.Errorf("extra fields do not match:\n\n%#v\n\n%#v", expected, srcinfo.Extra)
+	}
+
+	pkg, err := srcinfo.SplitPackage("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []ExtraField{
+		{Key: "tags", ArchDistroString: ArchDistroString{Value: "z"}},
+		{Key: "homepage", ArchDistroString: ArchDistroString{Value: "b"}},
+		{Key: "signer", ArchDistroString: ArchDistroString{Value: "me"}},
+	}
+	if !reflect.DeepEqual(pkg.Extra, expected) {
+		t.Errorf("merged extra fields do not match:\n\n%#v\n\n%#v", expected, pkg.Extra)
+	}
+
+	if !strings.Contains(srcinfo.String(), "\ttags_jammy = y\n") {
+		t.Errorf("registered field was not printed:\n%s", srcinfo)
+	}
+
+	_, err = ParseWithOptions(data+"\tsigner = you\n", ParseOptions{Dialect: &dialect})
+	if !errors.Is(err, ErrBaseFieldAfterPkgname) {
+		t.Errorf("error should be %v but was %v", ErrBaseFieldAfterPkgname, err)
+	}
+
+	_, err = ParseWithOptions(data+"\thomepage_jammy = c\n", ParseOptions{Dialect: &dialect})
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("error should be %v but was %v", ErrUnknownKey, err)
+	}
+}
+
+func TestSchemaBuiltinOrder(t *testing.T) {
+	fields := PacstallDialect.Schema.Fields()
+	if len(fields) != len(builtinFields) {
+		t.Fatalf("pacstall schema should contain every built in field")
+	}
+
+	for n, field := range fields {
+		if field.Name != builtinFields[n].Name || !field.IsBuiltin() {
+			t.Errorf("unexpected field %d: %#v", n, field)
+		}
+	}
+}
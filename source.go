@@ -0,0 +1,172 @@
+package srcinfo
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// vcsFragments lists the fragment kinds each VCS supports.
+var vcsFragments = map[string][]string{
+	"bzr":    {"revision"},
+	"fossil": {"branch", "commit", "tag"},
+	"git":    {"branch", "commit", "tag"},
+	"hg":     {"branch", "revision", "tag"},
+	"svn":    {"revision"},
+}
+
+// Source is a parsed entry of the source field, in the form:
+//
+//	[filename::][vcs+]url[#kind=value][?query]
+//
+// Fragments and queries are only parsed for VCS sources, for other sources
+// they are left as part of the URL.
+type Source struct {
+	Arch   string // Architecture the source is specific to, if any
+	Distro string // Distro the source is specific to, if any
+	Raw    string // The unparsed value
+
+	Filename string // Local filename given with "filename::", if any
+	URL      string // The URL without filename, VCS prefix, fragment or query
+	Scheme   string // The scheme of the URL, empty for local files
+	VCS      string // git, hg, svn, bzr or fossil, empty if not a VCS source
+
+	FragmentKind  string // branch, commit, tag or revision
+	FragmentValue string // The value of the fragment
+	Query         string // The query without the leading ?, such as "signed"
+	Signed        bool   // The query requests signature verification
+
+	Local bool // The source is a file next to the pacscript or PKGBUILD
+}
+
+// Name returns the filename the source is saved as. This is the filename
+// given with "filename::" or otherwise the last element of the URL, without
+// any ".git" suffix for git sources.
+func (s Source) Name() string {
+	if s.Filename != "" {
+		return s.Filename
+	}
+
+	name := path.Base(strings.TrimSuffix(s.URL, "/"))
+	if s.VCS == "git" {
+		name = strings.TrimSuffix(name, ".git")
+	}
+
+	return name
+}
+
+// ParseSource parses a single source entry.
+func ParseSource(value string) (Source, error) {
+	s := Source{Raw: value, URL: value}
+
+	if value == "" || value == EmptyOverride {
+		return Source{}, fmt.Errorf("Invalid source: source is empty")
+	}
+
+	if filename, url, ok := strings.Cut(value, "::"); ok {
+		if filename == "" || url == "" {
+			return Source{}, fmt.Errorf("Invalid source \"%s\": empty filename or url", value)
+		}
+
+		s.Filename = filename
+		s.URL = url
+	}
+
+	scheme, _, ok := strings.Cut(s.URL, "://")
+	if !ok {
+		s.Local = true
+		return s, nil
+	}
+
+	s.Scheme = scheme
+	if vcs, rest, ok := strings.Cut(scheme, "+"); ok {
+		if _, known := vcsFragments[vcs]; known {
+			s.VCS = vcs
+			s.Scheme = rest
+
+			// makepkg keeps the prefix for svn over ssh
+			if vcs != "svn" || rest != "ssh" {
+				s.URL = strings.TrimPrefix(s.URL, vcs+"+")
+			}
+		}
+	} else if _, known := vcsFragments[scheme]; known {
+		s.VCS = scheme
+	}
+
+	if s.VCS == "" {
+		return s, nil
+	}
+
+	url, fragment, hasFragment := strings.Cut(s.URL, "#")
+	if hasFragment {
+		fragment, s.Query, _ = cutLast(fragment, "?")
+	} else {
+		url, s.Query, _ = cutLast(url, "?")
+	}
+
+	s.URL = url
+	s.Signed = s.Query == "signed"
+
+	if hasFragment {
+		kind, fvalue, _ := strings.Cut(fragment, "=")
+		if fvalue == "" {
+			return Source{}, fmt.Errorf("Invalid source \"%s\": fragment \"%s\" has no value", value, fragment)
+		}
+
+		if !contains(vcsFragments[s.VCS], kind) {
+			return Source{}, fmt.Errorf("Invalid source \"%s\": %s does not support fragment \"%s\"", value, s.VCS, kind)
+		}
+
+		s.FragmentKind = kind
+		s.FragmentValue = fvalue
+	}
+
+	return s, nil
+}
+
+// ParseSources parses the value of each ArchDistroString, keeping the arch and
+// distro of each. Empty overrides are skipped.
+func ParseSources(values []ArchDistroString) ([]Source, error) {
+	sources := make([]Source, 0, len(values))
+
+	for _, value := range values {
+		if value.Value == EmptyOverride {
+			continue
+		}
+
+		source, err := ParseSource(value.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		source.Arch = value.Arch
+		source.Distro = value.Distro
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// Sources parses every entry of the source field, see ParseSources.
+func (si *Srcinfo) Sources() ([]Source, error) {
+	return ParseSources(si.Source)
+}
+
+// cutLast is strings.Cut but cuts around the last instance of sep.
+func cutLast(s, sep string) (string, string, bool) {
+	if n := strings.LastIndex(s, sep); n != -1 {
+		return s[:n], s[n+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+func contains(list []string, str string) bool {
+	for _, v := range list {
+		if v == str {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package srcinfo
+
+import (
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		value  string
+		source Source
+		name   string
+	}{
+		{"config", Source{URL: "config", Local: true}, "config"},
+		{"foo.tar.gz::https://example.com/download?id=1", Source{Filename: "foo.tar.gz", URL: "https://example.com/download?id=1", Scheme: "https"}, "foo.tar.gz"},
+		{"https://example.com/foo-1.0.tar.gz", Source{URL: "https://example.com/foo-1.0.tar.gz", Scheme: "https"}, "foo-1.0.tar.gz"},
+		{"git+https://github.com/foo/bar.git#tag=v1?signed", Source{URL: "https://github.com/foo/bar.git", Scheme: "https", VCS: "git", FragmentKind: "tag", FragmentValue: "v1", Query: "signed", Signed: true}, "bar"},
+		{"baz::git+https://github.com/foo/bar.git?signed", Source{Filename: "baz", URL: "https://github.com/foo/bar.git", Scheme: "https", VCS: "git", Query: "signed", Signed: true}, "baz"},
+		{"git://example.com/bar#branch=main", Source{URL: "git://example.com/bar", Scheme: "git", VCS: "git", FragmentKind: "branch", FragmentValue: "main"}, "bar"},
+		{"hg+https://example.com/bar#revision=12", Source{URL: "https://example.com/bar", Scheme: "https", VCS: "hg", FragmentKind: "revision", FragmentValue: "12"}, "bar"},
+		{"svn+ssh://example.com/trunk/", Source{URL: "svn+ssh://example.com/trunk/", Scheme: "ssh", VCS: "svn"}, "trunk"},
+		{"bzr+lp:foo", Source{URL: "bzr+lp:foo", Local: true}, "bzr+lp:foo"},
+	}
+
+	for _, test := range tests {
+		source, err := ParseSource(test.value)
+		if err != nil {
+			t.Errorf("%s: %s", test.value, err)
+			continue
+		}
+
+		test.source.Raw = test.value
+		if source != test.source {
+			t.Errorf("%s: expected %#v got %#v", test.value, test.source, source)
+		}
+
+		if source.Name() != test.name {
+			t.Errorf("%s: expected name %s got %s", test.value, test.name, source.Name())
+		}
+	}
+
+	bad := []string{
+		"",
+		EmptyOverride,
+		"::https://example.com/foo",
+		"foo::",
+		"git+https://example.com/foo#tag",
+		"git+https://example.com/foo#revision=1",
+		"svn+https://example.com/foo#branch=main",
+	}
+
+	for _, value := range bad {
+		if _, err := ParseSource(value); err == nil {
+			t.Errorf("%s: should have errored", value)
+		}
+	}
+}
+
+func TestParseSources(t *testing.T) {
+	srcinfo, err := Parse(srcinfoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcinfo.Source = append(srcinfo.Source, ArchDistroString{"x86_64", "", EmptyOverride})
+	srcinfo.Source = append(srcinfo.Source, ArchDistroString{"x86_64", "jammy", "foo::git+https://example.com/foo"})
+
+	sources, err := srcinfo.Sources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sources) != len(srcinfo.Source)-1 {
+		t.Fatalf("expected %d sources got %d", len(srcinfo.Source)-1, len(sources))
+	}
+
+	if sources[8].Name() != "enable_additional_cpu_optimizations-20180509.tar.gz" || !sources[4].Local {
+		t.Errorf("unexpected sources: %#v", sources)
+	}
+
+	last := sources[len(sources)-1]
+	if last.Arch != "x86_64" || last.Distro != "jammy" || last.VCS != "git" {
+		t.Errorf("arch and distro were not kept: %#v", last)
+	}
+}
@@ -0,0 +1,456 @@
+// Package spdx parses the license field of a srcinfo as SPDX license
+// expressions such as "MIT OR Apache-2.0" and
+// "GPL-2.0-only WITH Classpath-exception-2.0".
+//
+// Identifiers are checked against a list of SPDX license and exception
+// identifiers embedded in the package. The legacy license names used by
+// pacman and older pacscripts, such as "GPL2" and "custom:foo", are mapped to
+// their SPDX equivalent.
+package spdx
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/pacstall/go-srcinfo"
+)
+
+//go:embed licenses.txt
+var licensesData string
+
+//go:embed exceptions.txt
+var exceptionsData string
+
+// licenses and exceptions map the lower case form of each identifier to its
+// canonical form, as identifiers are matched case insensitively.
+var (
+	licenses   = readList(licensesData)
+	exceptions = readList(exceptionsData)
+)
+
+func readList(data string) map[string]string {
+	list := make(map[string]string)
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		list[strings.ToLower(line)] = line
+	}
+
+	return list
+}
+
+// legacy maps the lower case form of legacy license names to SPDX
+// identifiers. Names with no single SPDX equivalent, such as "BSD", are left
+// out and must be given as an SPDX identifier or with custom:.
+var legacy = map[string]string{
+	"agpl":         "AGPL-3.0-or-later",
+	"agpl3":        "AGPL-3.0-only",
+	"agplv3":       "AGPL-3.0-only",
+	"apache":       "Apache-2.0",
+	"apache2":      "Apache-2.0",
+	"artistic2.0":  "Artistic-2.0",
+	"boost":        "BSL-1.0",
+	"cddl":         "CDDL-1.0",
+	"cpl":          "CPL-1.0",
+	"epl":          "EPL-1.0",
+	"fdl":          "GFDL-1.3-or-later",
+	"fdl1.2":       "GFDL-1.2-only",
+	"fdl1.3":       "GFDL-1.3-only",
+	"gpl":          "GPL-2.0-or-later",
+	"gpl2":         "GPL-2.0-only",
+	"gplv2":        "GPL-2.0-only",
+	"gpl3":         "GPL-3.0-only",
+	"gplv3":        "GPL-3.0-only",
+	"lgpl":         "LGPL-2.1-or-later",
+	"lgpl2.1":      "LGPL-2.1-only",
+	"lgplv2.1":     "LGPL-2.1-only",
+	"lgpl3":        "LGPL-3.0-only",
+	"lgplv3":       "LGPL-3.0-only",
+	"lppl":         "LPPL-1.3c",
+	"mpl":          "MPL-1.1",
+	"mpl2":         "MPL-2.0",
+	"perlartistic": "Artistic-1.0-Perl",
+	"php":          "PHP-3.01",
+	"psf":          "PSF-2.0",
+	"ruby":         "Ruby",
+	"w3c":          "W3C",
+	"zlib":         "Zlib",
+	"zpl":          "ZPL-2.1",
+}
+
+// Op is an operator joining two expressions.
+type Op string
+
+// Operators, AND binding tighter than OR.
+const (
+	OpNone Op = ""
+	OpAnd  Op = "AND"
+	OpOr   Op = "OR"
+)
+
+// Expr is a parsed license expression. An Expr with Op set to OpNone is a
+// single license, otherwise Left and Right are joined by Op.
+type Expr struct {
+	Op          Op
+	Left, Right *Expr
+
+	License   string // SPDX identifier or LicenseRef-
+	OrLater   bool   // The identifier was followed by +
+	Exception string // Exception given with WITH, if any
+	Legacy    string // The legacy name the license was mapped from, if any
+}
+
+// String formats the expression in its canonical form. Parentheses are only
+// added where they are needed.
+func (e *Expr) String() string {
+	if e.Op == OpNone {
+		str := e.License
+		if e.OrLater {
+			str += "+"
+		}
+
+		if e.Exception != "" {
+			str += " WITH " + e.Exception
+		}
+
+		return str
+	}
+
+	left, right := e.Left.String(), e.Right.String()
+
+	if e.Op == OpAnd && e.Left.Op == OpOr {
+		left = "(" + left + ")"
+	}
+
+	// the tree is built left to right, so a right hand side with the same
+	// operator came from parentheses
+	if e.Right.Op != OpNone && !(e.Op == OpOr && e.Right.Op == OpAnd) {
+		right = "(" + right + ")"
+	}
+
+	return left + " " + string(e.Op) + " " + right
+}
+
+// Licenses returns every license identifier in the expression, in the order
+// they appear.
+func (e *Expr) Licenses() []string {
+	if e.Op == OpNone {
+		return []string{e.License}
+	}
+
+	return append(e.Left.Licenses(), e.Right.Licenses()...)
+}
+
+// Validate checks that every license and exception identifier in the
+// expression is on the SPDX list. LicenseRef- and DocumentRef- identifiers are
+// always accepted.
+func (e *Expr) Validate() error {
+	if e.Op != OpNone {
+		if err := e.Left.Validate(); err != nil {
+			return err
+		}
+
+		return e.Right.Validate()
+	}
+
+	if !IsLicense(e.License) && !isRef(e.License) {
+		return fmt.Errorf("Unknown SPDX license \"%s\"", e.License)
+	}
+
+	if e.Exception != "" && !IsException(e.Exception) {
+		return fmt.Errorf("Unknown SPDX exception \"%s\"", e.Exception)
+	}
+
+	return nil
+}
+
+// IsLicense reports whether id is an SPDX license identifier, ignoring case.
+func IsLicense(id string) bool {
+	_, ok := licenses[strings.ToLower(id)]
+	return ok
+}
+
+// IsException reports whether id is an SPDX exception identifier, ignoring
+// case.
+func IsException(id string) bool {
+	_, ok := exceptions[strings.ToLower(id)]
+	return ok
+}
+
+func isRef(id string) bool {
+	return strings.HasPrefix(id, "LicenseRef-") || strings.HasPrefix(id, "DocumentRef-")
+}
+
+// Parse parses a license entry and validates it, see Validate.
+//
+// An entry of "custom" or starting with "custom:" is mapped to a LicenseRef-
+// identifier, any characters not allowed in an identifier being replaced with
+// "-". Legacy names such as "GPL2" are mapped to SPDX identifiers, both as a
+// whole entry and as identifiers within an expression. Identifiers are
+// changed to the case used by the SPDX list.
+func Parse(str string) (*Expr, error) {
+	expr, err := parse(str)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid license \"%s\": %s", str, err.Error())
+	}
+
+	if err := expr.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid license \"%s\": %s", str, err.Error())
+	}
+
+	return expr, nil
+}
+
+func parse(str string) (*Expr, error) {
+	trimmed := strings.TrimSpace(str)
+
+	if name, ok := strings.CutPrefix(trimmed, "custom"); ok && (name == "" || name[0] == ':') {
+		name = strings.TrimSpace(strings.TrimPrefix(name, ":"))
+		if name == "" {
+			name = "custom"
+		}
+
+		return &Expr{License: "LicenseRef-" + refName(name), Legacy: trimmed}, nil
+	}
+
+	if id, ok := legacy[strings.ToLower(trimmed)]; ok {
+		return &Expr{License: id, Legacy: trimmed}, nil
+	}
+
+	p := &parser{tokens: tokenize(trimmed)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("license is empty")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.n != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected \"%s\"", p.tokens[p.n])
+	}
+
+	return expr, nil
+}
+
+// refName replaces every character that may not appear in a LicenseRef-
+// identifier with "-".
+func refName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if isIDChar(r) && r != ':' && r != '+' {
+			return r
+		}
+
+		return '-'
+	}, name)
+}
+
+func isIDChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '.' || r == '-' || r == '+' || r == ':'
+}
+
+// tokenize splits an expression into parentheses and words.
+func tokenize(str string) []string {
+	var tokens []string
+	start := -1
+
+	for n, r := range str {
+		if r == '(' || r == ')' || r == ' ' || r == '\t' {
+			if start != -1 {
+				tokens = append(tokens, str[start:n])
+				start = -1
+			}
+
+			if r == '(' || r == ')' {
+				tokens = append(tokens, string(r))
+			}
+		} else if start == -1 {
+			start = n
+		}
+	}
+
+	if start != -1 {
+		tokens = append(tokens, str[start:])
+	}
+
+	return tokens
+}
+
+// parser is a recursive descent parser over the tokens of an expression.
+type parser struct {
+	tokens []string
+	n      int
+}
+
+func (p *parser) peek() string {
+	if p.n < len(p.tokens) {
+		return p.tokens[p.n]
+	}
+
+	return ""
+}
+
+// isOp reports whether the next token is the operator op, which may be
+// written in upper or lower case.
+func (p *parser) isOp(op string) bool {
+	next := p.peek()
+	return next == op || next == strings.ToLower(op)
+}
+
+func (p *parser) parseOr() (*Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("OR") {
+		p.n++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expr{Op: OpOr, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (*Expr, error) {
+	left, err := p.parseWith()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("AND") {
+		p.n++
+
+		right, err := p.parseWith()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expr{Op: OpAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseWith() (*Expr, error) {
+	token := p.peek()
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		p.n++
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+
+		p.n++
+		return expr, nil
+	case token == ")" || p.isOp("AND") || p.isOp("OR") || p.isOp("WITH"):
+		return nil, fmt.Errorf("unexpected \"%s\"", token)
+	}
+
+	p.n++
+	expr, err := license(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isOp("WITH") {
+		p.n++
+
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, fmt.Errorf("missing exception after WITH")
+		}
+
+		p.n++
+		expr.Exception = exception
+		if canonical, ok := exceptions[strings.ToLower(exception)]; ok {
+			expr.Exception = canonical
+		}
+	}
+
+	return expr, nil
+}
+
+// license parses a single license identifier.
+func license(token string) (*Expr, error) {
+	for _, r := range token {
+		if !isIDChar(r) {
+			return nil, fmt.Errorf("invalid character %q in \"%s\"", r, token)
+		}
+	}
+
+	id, orLater := strings.CutSuffix(token, "+")
+	if id == "" || strings.Contains(id, "+") {
+		return nil, fmt.Errorf("invalid license \"%s\"", token)
+	}
+
+	expr := &Expr{License: id, OrLater: orLater}
+
+	if canonical, ok := licenses[strings.ToLower(id)]; ok {
+		expr.License = canonical
+	} else if mapped, ok := legacy[strings.ToLower(id)]; ok {
+		expr.License = mapped
+		expr.Legacy = id
+	}
+
+	return expr, nil
+}
+
+// ParseAll parses every license entry. Empty overrides are skipped.
+func ParseAll(values []string) ([]*Expr, error) {
+	exprs := make([]*Expr, 0, len(values))
+
+	for _, value := range values {
+		if value == srcinfo.EmptyOverride {
+			continue
+		}
+
+		expr, err := Parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		exprs = append(exprs, expr)
+	}
+
+	return exprs, nil
+}
+
+// Licenses parses the license entries of every package of a srcinfo after
+// merging, as returned by SplitPackages. The result maps each pkgname to its
+// parsed entries.
+func Licenses(si *srcinfo.Srcinfo) (map[string][]*Expr, error) {
+	result := make(map[string][]*Expr)
+
+	for _, pkg := range si.SplitPackages() {
+		exprs, err := ParseAll(pkg.License)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkg.Pkgname, err)
+		}
+
+		result[pkg.Pkgname] = exprs
+	}
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package spdx
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pacstall/go-srcinfo"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		str       string
+		canonical string
+		licenses  []string
+	}{
+		{"MIT", "MIT", []string{"MIT"}},
+		{"mit", "MIT", []string{"MIT"}},
+		{"MIT OR Apache-2.0", "MIT OR Apache-2.0", []string{"MIT", "Apache-2.0"}},
+		{"MIT or apache-2.0", "MIT OR Apache-2.0", []string{"MIT", "Apache-2.0"}},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL-2.0-only"}},
+		{"GPL-2.0+", "GPL-2.0+", []string{"GPL-2.0"}},
+		{"MIT AND (LGPL-2.1-or-later OR BSD-3-Clause)", "MIT AND (LGPL-2.1-or-later OR BSD-3-Clause)", []string{"MIT", "LGPL-2.1-or-later", "BSD-3-Clause"}},
+		{"(MIT OR ISC) AND Zlib", "(MIT OR ISC) AND Zlib", []string{"MIT", "ISC", "Zlib"}},
+		{"((MIT))", "MIT", []string{"MIT"}},
+		{"MIT OR ISC AND Zlib", "MIT OR ISC AND Zlib", []string{"MIT", "ISC", "Zlib"}},
+		{"LicenseRef-foo AND MIT", "LicenseRef-foo AND MIT", []string{"LicenseRef-foo", "MIT"}},
+		{"GPL2", "GPL-2.0-only", []string{"GPL-2.0-only"}},
+		{"GPL3 OR MIT", "GPL-3.0-only OR MIT", []string{"GPL-3.0-only", "MIT"}},
+		{"PerlArtistic", "Artistic-1.0-Perl", []string{"Artistic-1.0-Perl"}},
+		{"custom", "LicenseRef-custom", []string{"LicenseRef-custom"}},
+		{"custom:foo", "LicenseRef-foo", []string{"LicenseRef-foo"}},
+		{"custom: SIL Open Font License v1.1", "LicenseRef-SIL-Open-Font-License-v1.1", []string{"LicenseRef-SIL-Open-Font-License-v1.1"}},
+	}
+
+	for _, test := range tests {
+		expr, err := Parse(test.str)
+		if err != nil {
+			t.Errorf("%s: %s", test.str, err)
+			continue
+		}
+
+		if expr.String() != test.canonical {
+			t.Errorf("%s: expected canonical form \"%s\" got \"%s\"", test.str, test.canonical, expr.String())
+		}
+
+		if !reflect.DeepEqual(expr.Licenses(), test.licenses) {
+			t.Errorf("%s: expected licenses %v got %v", test.str, test.licenses, expr.Licenses())
+		}
+
+		again, err := Parse(expr.String())
+		if err != nil || again.String() != expr.String() {
+			t.Errorf("%s: canonical form does not round trip: %v %v", test.str, again, err)
+		}
+	}
+
+	expr, _ := Parse("GPL2")
+	if expr.Legacy != "GPL2" {
+		t.Errorf("expected legacy name GPL2 got %s", expr.Legacy)
+	}
+}
+
+func TestUncommonIdentifiers(t *testing.T) {
+	ids := []string{
+		"Elastic-2.0",
+		"MIT-open-group",
+		"Sendmail",
+		"OLDAP-2.8",
+		"Unicode-TOU",
+		"3D-Slicer-1.0",
+		"GPL-3.0-or-later WITH openvpn-openssl-exception",
+		"LGPL-2.1-only WITH cryptsetup-OpenSSL-exception",
+		"GPL-2.0-or-later WITH x11vnc-openssl-exception",
+	}
+
+	for _, id := range ids {
+		expr, err := Parse(id)
+		if err != nil {
+			t.Errorf("%s: %s", id, err)
+		} else if expr.String() != id {
+			t.Errorf("%s: expected canonical form \"%s\" got \"%s\"", id, id, expr.String())
+		}
+	}
+}
+
+func TestParseBad(t *testing.T) {
+	bad := []string{
+		"",
+		"NotALicense",
+		"BSD",
+		"MIT OR",
+		"OR MIT",
+		"MIT AND AND ISC",
+		"(MIT",
+		"MIT)",
+		"MIT ISC",
+		"MIT WITH",
+		"MIT WITH NotAnException",
+		"(MIT OR ISC) WITH Classpath-exception-2.0",
+		"MIT++",
+		"MIT, ISC",
+		"MIT And ISC",
+	}
+
+	for _, str := range bad {
+		if _, err := Parse(str); err == nil {
+			t.Errorf("%s: should have errored", str)
+		}
+	}
+}
+
+func TestLicenses(t *testing.T) {
+	si, err := srcinfo.Parse(`pkgbase = foo
+	pkgver = 1
+	license = GPL2
+	license = custom:foo
+pkgname = foo
+pkgname = foo-docs
+	license = CC-BY-SA-4.0
+pkgname = foo-none
+	license =`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	licenses, err := Licenses(si)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"foo":      {"GPL-2.0-only", "LicenseRef-foo"},
+		"foo-docs": {"CC-BY-SA-4.0"},
+		"foo-none": {},
+	}
+
+	for pkgname, ids := range expected {
+		exprs, ok := licenses[pkgname]
+		if !ok || len(exprs) != len(ids) {
+			t.Errorf("%s: expected %v got %v", pkgname, ids, exprs)
+			continue
+		}
+
+		for n, expr := range exprs {
+			if expr.String() != ids[n] {
+				t.Errorf("%s: expected %s got %s", pkgname, ids[n], expr.String())
+			}
+		}
+	}
+
+	si.License = append(si.License, "NotALicense")
+	if _, err := Licenses(si); err == nil {
+		t.Error("unknown license should have errored")
+	}
+}
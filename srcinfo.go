@@ -1,7 +1,10 @@
 // Package srcinfo is a parser for srcinfo files. Typically generated by
-// makepkg, part of the pacman package manager.
+// pacstall, or by makepkg, part of the pacman package manager.
 //
-// Split packages and architecture dependent fields are fully supported.
+// Split packages as well as architecture and distro dependent fields are
+// fully supported. The rules of each kind of srcinfo are described by a
+// Dialect, PacstallDialect is used by default and ArchDialect may be used for
+// srcinfos from the AUR.
 //
 // This Package aims to parse srcinfos but not interpret them in any way.
 // All values are fundamentally strings, other tools should be used for
@@ -10,6 +13,7 @@ package srcinfo
 
 import (
 	"fmt"
+	"slices"
 )
 
 // ArchDistroString describes string values that may be architecture dependent.
@@ -21,6 +25,14 @@ type ArchDistroString struct {
 	Value  string // Value
 }
 
+// ExtraField describes a field with a key that is not otherwise known, such as
+// makepkg's install or validpgpkeys. These fields are kept in the order they
+// were parsed so that they can be printed again by String.
+type ExtraField struct {
+	Key string // Field name without the arch or distro suffix
+	ArchDistroString
+}
+
 // Package describes the fields of a pkgbuild that may be overwritten by
 // in build_<pkgname> function.
 type Package struct {
@@ -45,31 +57,32 @@ type Package struct {
 	Suggests       []ArchDistroString
 	Backup         []string
 	Repology       []string
+	Extra          []ExtraField
 }
 
 // PackageBase describes the fields of a pkgbuild that may not be overwritten
 // in package_<pkgname> function.
 type PackageBase struct {
-	Pkgbase         string
-	Pkgver          string
-	Pkgrel          string
-	Epoch           string
-	Mask            []string
-	Compatible      []string
-	Incompatible    []string
-	Maintainer      []string
-	Source          []ArchDistroString
-	NoExtract       []string
-	NoSubmodules    []string
-	MD5Sums         []ArchDistroString
-	SHA1Sums        []ArchDistroString
-	SHA224Sums      []ArchDistroString
-	SHA256Sums      []ArchDistroString
-	SHA384Sums      []ArchDistroString
-	SHA512Sums      []ArchDistroString
-	B2Sums          []ArchDistroString
-	MakeDepends     []ArchDistroString
-	MakeConflicts   []ArchDistroString
+	Pkgbase       string
+	Pkgver        string
+	Pkgrel        string
+	Epoch         string
+	Mask          []string
+	Compatible    []string
+	Incompatible  []string
+	Maintainer    []string
+	Source        []ArchDistroString
+	NoExtract     []string
+	NoSubmodules  []string
+	MD5Sums       []ArchDistroString
+	SHA1Sums      []ArchDistroString
+	SHA224Sums    []ArchDistroString
+	SHA256Sums    []ArchDistroString
+	SHA384Sums    []ArchDistroString
+	SHA512Sums    []ArchDistroString
+	B2Sums        []ArchDistroString
+	MakeDepends   []ArchDistroString
+	MakeConflicts []ArchDistroString
 }
 
 // Srcinfo represents a full srcinfo. All global fields are defined here while
@@ -83,6 +96,9 @@ type Srcinfo struct {
 	PackageBase           // Fields that only apply to the package base
 	Package               // Fields that apply to the package globally
 	Packages    []Package // Fields for each package this package base contains
+
+	registry *Registry    // Copy of the Registry the srcinfo was parsed with
+	aliases  *ArchAliases // Copy of the ArchAliases the srcinfo was parsed with
 }
 
 // EmptyOverride is used to signal when a value has been overridden with an
@@ -92,6 +108,14 @@ type Srcinfo struct {
 // For example "pkgdesc=”" is an empty override on the pkgdesc which would
 // lead to the line "pkgdesc=" in the srcinfo.
 //
+// An empty override has the same meaning wherever it appears: the key has no
+// values, as if it was not set. In a split package it removes the values the
+// package base gives that key, so "depends_jammy=" lets the generic depends
+// of the package base apply on jammy. An empty override of a key without a
+// distro, such as "depends=", also removes the distro variants of the package
+// base, clearing the field. In the package base, and when resolving with
+// Target.Values, an empty override contributes no values and is skipped.
+//
 // This value is used internally to store empty overrides, mainly to avoid
 // using string pointers. It is possible to check for empty overrides using
 // the Packages slice in Packagebase.
@@ -99,11 +123,12 @@ type Srcinfo struct {
 // During normal use with the SplitPackage function this value will be
 // converted back to an empty string, or removed entirely for slice values.
 // This means the this value can be completley ignored unless you are
-// explicitly looking for empty overrides.
+// explicitly looking for empty overrides, which IsOverridden reports.
 const EmptyOverride = "\x00"
 
 // Version formats a version string from the epoch, pkgver and pkgrel of the
-// srcinfo. In the format [epoch:]pkgver-pkgrel.
+// srcinfo. In the format [epoch:]pkgver-pkgrel. Use ParsedVersion to get a
+// Version that can be compared.
 func (si *Srcinfo) Version() string {
 	if si.Epoch == "" {
 		return si.Pkgver + "-" + si.Pkgrel
@@ -118,7 +143,7 @@ func (si *Srcinfo) SplitPackages() []*Package {
 	pkgs := make([]*Package, 0, len(si.Packages))
 
 	for _, pkg := range si.Packages {
-		pkgs = append(pkgs, mergeSplitPackage(&si.Package, &pkg))
+		pkgs = append(pkgs, mergeSplitPackage(si.aliases, &si.Package, &pkg))
 	}
 
 	return pkgs
@@ -129,23 +154,56 @@ func (si *Srcinfo) SplitPackages() []*Package {
 // the Package.
 //
 // Note slice values will be passed by reference, it is not recommended you
-// modify this struct after it is returned.
+// modify this struct after it is returned. Use Package.Clone to get a copy
+// that may be modified, or a View to share a srcinfo between goroutines.
 func (si *Srcinfo) SplitPackage(pkgname string) (*Package, error) {
 	for n := range si.Packages {
 		if si.Packages[n].Pkgname == pkgname {
-			return mergeSplitPackage(&si.Package, &si.Packages[n]), nil
+			return mergeSplitPackage(si.aliases, &si.Package, &si.Packages[n]), nil
 		}
 	}
 
 	return nil, fmt.Errorf("Package \"%s\" is not part of the package base \"%s\"", pkgname, si.Pkgbase)
 }
 
-func mergeArchSlice(global, override []ArchDistroString) []ArchDistroString {
-	overridden := make(map[string]struct{})
+// archDistro is the suffix of a key, used to decide which global values are
+// overridden by a split package. The arch is canonical so that aliases
+// override each other.
+type archDistro struct {
+	arch   string
+	distro string
+}
+
+// replaced reports whether a global value with the given suffix is
+// replaced by a split package that overrode the suffixes in overridden.
+// Overriding the key without a distro, such as depends or depends_amd64, also
+// replaces every distro variant of it for the same arch, as the split package
+// then defines the field itself.
+func replaced(overridden map[archDistro]struct{}, ad archDistro) bool {
+	if _, ok := overridden[ad]; ok {
+		return true
+	}
+
+	_, ok := overridden[archDistro{ad.arch, ""}]
+	return ok
+}
+
+// mergeArchSlice merges the values of a split package into the global
+// values, dropping every EmptyOverride. global is returned as is if there is
+// nothing to merge or drop.
+func mergeArchSlice(aliases *ArchAliases, global, override []ArchDistroString) []ArchDistroString {
+	hasEmpty := slices.ContainsFunc(global, func(v ArchDistroString) bool {
+		return v.Value == EmptyOverride
+	})
+	if len(override) == 0 && !hasEmpty {
+		return global
+	}
+
+	overridden := make(map[archDistro]struct{})
 	merged := make([]ArchDistroString, 0, len(override))
 
 	for _, v := range override {
-		overridden[v.Arch] = struct{}{}
+		overridden[archDistro{aliases.Canonical(v.Arch), v.Distro}] = struct{}{}
 		if v.Value == EmptyOverride {
 			continue
 		}
@@ -153,7 +211,7 @@ func mergeArchSlice(global, override []ArchDistroString) []ArchDistroString {
 	}
 
 	for _, v := range global {
-		if _, ok := overridden[v.Arch]; !ok {
+		if v.Value != EmptyOverride && !replaced(overridden, archDistro{aliases.Canonical(v.Arch), v.Distro}) {
 			merged = append(merged, v)
 		}
 	}
@@ -161,91 +219,158 @@ func mergeArchSlice(global, override []ArchDistroString) []ArchDistroString {
 	return merged
 }
 
-func mergeSplitPackage(base, split *Package) *Package {
+// mergeExtra is mergeArchSlice for Extra, where values are also matched on
+// their key.
+func mergeExtra(aliases *ArchAliases, global, override []ExtraField) []ExtraField {
+	hasEmpty := slices.ContainsFunc(global, func(v ExtraField) bool {
+		return v.Value == EmptyOverride
+	})
+	if len(override) == 0 && !hasEmpty {
+		return global
+	}
+
+	overridden := make(map[string]map[archDistro]struct{})
+	merged := make([]ExtraField, 0, len(override))
+
+	for _, v := range override {
+		if overridden[v.Key] == nil {
+			overridden[v.Key] = make(map[archDistro]struct{})
+		}
+
+		overridden[v.Key][archDistro{aliases.Canonical(v.Arch), v.Distro}] = struct{}{}
+		if v.Value == EmptyOverride {
+			continue
+		}
+		merged = append(merged, v)
+	}
+
+	for _, v := range global {
+		if v.Value != EmptyOverride && !replaced(overridden[v.Key], archDistro{aliases.Canonical(v.Arch), v.Distro}) {
+			merged = append(merged, v)
+		}
+	}
+
+	return merged
+}
+
+func mergeSplitPackage(aliases *ArchAliases, base, split *Package) *Package {
 	pkg := &Package{}
 	*pkg = *base
 
 	pkg.Pkgname = split.Pkgname
 
-	if split.Pkgdesc != "" {
-		pkg.Pkgdesc = split.Pkgdesc
-	}
+	for _, field := range builtinFields {
+		if field.Scope != ScopePackage {
+			continue
+		}
 
-	if split.URL != "" {
-		pkg.URL = split.URL
-	}
-	
-	if split.Priority != "" {
-		pkg.Priority = split.Priority
-	}
-	
-	if len(split.Arch) != 0 {
-		pkg.Arch = split.Arch
+		switch {
+		case field.str != nil:
+			value := field.str(nil, pkg)
+			if override := *field.str(nil, split); override != "" {
+				*value = override
+			}
+
+			if *value == EmptyOverride {
+				*value = ""
+			}
+		case field.strs != nil:
+			values := field.strs(nil, pkg)
+			if override := *field.strs(nil, split); len(override) != 0 {
+				*values = override
+			}
+
+			*values = removeEmptyOverrides(*values)
+		case field.ads != nil:
+			values := field.ads(nil, pkg)
+			*values = mergeArchSlice(aliases, *values, *field.ads(nil, split))
+		}
 	}
 
-	if len(split.License) != 0 {
-		pkg.License = split.License
-	}
+	pkg.Extra = mergeExtra(aliases, pkg.Extra, split.Extra)
 
-	if len(split.Gives) != 0 {
-		pkg.Gives = mergeArchSlice(pkg.Gives, split.Gives)
-	}
+	return pkg
+}
 
-	if len(split.Depends) != 0 {
-		pkg.Depends = mergeArchSlice(pkg.Depends, split.Depends)
-	}
+// removeEmptyOverrides returns values without any EmptyOverride. values is
+// returned as is if it does not contain one.
+func removeEmptyOverrides(values []string) []string {
+	for n, v := range values {
+		if v != EmptyOverride {
+			continue
+		}
 
-	if len(split.CheckDepends) != 0 {
-		pkg.CheckDepends = mergeArchSlice(pkg.CheckDepends, split.CheckDepends)
-	}
-	
-	if len(split.OptDepends) != 0 {
-		pkg.OptDepends = mergeArchSlice(pkg.OptDepends, split.OptDepends)
-	}
+		kept := append(make([]string, 0, len(values)-1), values[:n]...)
+		for _, v := range values[n+1:] {
+			if v != EmptyOverride {
+				kept = append(kept, v)
+			}
+		}
 
-	if len(split.Pacdeps) != 0 {
-		pkg.Pacdeps = mergeArchSlice(pkg.Pacdeps, split.Pacdeps)
+		return kept
 	}
 
-	if len(split.CheckConflicts) != 0 {
-		pkg.CheckConflicts = mergeArchSlice(pkg.CheckConflicts, split.CheckConflicts)
-	}
-	
-	if len(split.Conflicts) != 0 {
-		pkg.Conflicts = mergeArchSlice(pkg.Conflicts, split.Conflicts)
-	}
-	
-	if len(split.Provides) != 0 {
-		pkg.Provides = mergeArchSlice(pkg.Provides, split.Provides)
-	}
+	return values
+}
 
-	if len(split.Breaks) != 0 {
-		pkg.Breaks = mergeArchSlice(pkg.Breaks, split.Breaks)
-	}
-	
-	if len(split.Replaces) != 0 {
-		pkg.Replaces = mergeArchSlice(pkg.Replaces, split.Replaces)
+// IsOverridden reports whether the package pkgname sets field itself rather
+// than using the value of the package base, and whether it does so only to
+// clear the value with an empty override. field is a key without any arch or
+// distro suffix, an arch or distro dependent field is overridden if any of
+// its suffixes is.
+//
+// Both are false if pkgname is not part of the srcinfo or field may not be
+// overridden.
+func (si *Srcinfo) IsOverridden(pkgname, field string) (overridden bool, cleared bool) {
+	var split *Package
+	for n := range si.Packages {
+		if si.Packages[n].Pkgname == pkgname {
+			split = &si.Packages[n]
+		}
 	}
 
-	if len(split.Enhances) != 0 {
-		pkg.Enhances = mergeArchSlice(pkg.Enhances, split.Enhances)
+	if split == nil {
+		return false, false
 	}
 
-	if len(split.Recommends) != 0 {
-		pkg.Recommends = mergeArchSlice(pkg.Recommends, split.Recommends)
+	var values []string
+
+	for _, f := range builtinFields {
+		if f.Name != field {
+			continue
+		}
+
+		switch {
+		case f.Scope != ScopePackage:
+			return false, false
+		case f.str != nil:
+			if value := *f.str(nil, split); value != "" {
+				values = append(values, value)
+			}
+		case f.strs != nil:
+			values = *f.strs(nil, split)
+		case f.ads != nil:
+			for _, v := range *f.ads(nil, split) {
+				values = append(values, v.Value)
+			}
+		}
 	}
 
-	if len(split.Suggests) != 0 {
-		pkg.Suggests = mergeArchSlice(pkg.Suggests, split.Suggests)
+	for _, v := range split.Extra {
+		if v.Key == field {
+			values = append(values, v.Value)
+		}
 	}
 
-	if len(split.Backup) != 0 {
-		pkg.Backup = split.Backup
+	if len(values) == 0 {
+		return false, false
 	}
 
-	if len(split.Repology) != 0 {
-		pkg.Repology = split.Repology
+	for _, v := range values {
+		if v != EmptyOverride {
+			return true, false
+		}
 	}
 
-	return pkg
+	return true, true
 }
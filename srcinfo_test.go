@@ -1,7 +1,10 @@
 package srcinfo
 
 import (
+	"errors"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -165,6 +168,7 @@ var goodSrcinfos = [...]string{
 	"diction",
 	"discord",
 	"displaylink",
+	"distro_override",
 	"dkms-pl2501",
 	"dmtx-utils",
 	"dolphin-megasync-git",
@@ -670,24 +674,30 @@ var goodSrcinfos = [...]string{
 	"zsurf-git",
 }
 
-var badSrcinfos = [...]string{
-	"any_field",
-	"base_field_after_pkgname",
-	"base_field_after_pkgname2",
-	"empty",
-	"invalid_arch",
-	"multiple_pkgbase",
-	"multiple_pkgname",
-	"no_arch",
-	"no_key",
-	"no_file",
-	"no_pkgbase",
-	"no_pkgname",
-	"no_pkgrel",
-	"no_pkgver",
-	//"no_value",
-	"pkgname_before_pkgbase",
-	//"unknown_key",
+var badSrcinfos = [...]struct {
+	name string
+	opts ParseOptions
+	err  string
+	kind error
+}{
+	{"any_field", ParseOptions{}, "arch \"any\" is not allowed", ErrInvalidArch},
+	{"base_field_after_pkgname", ParseOptions{}, "key \"noextract\" can not occur after pkgname", ErrBaseFieldAfterPkgname},
+	{"base_field_after_pkgname2", ParseOptions{}, "key \"makedepends\" can not occur after pkgname", ErrBaseFieldAfterPkgname},
+	{"empty", ParseOptions{}, "No pkgbase field", ErrMissingPkgbase},
+	{"invalid_arch", ParseOptions{Registry: DefaultRegistry()}, "unknown distro or arch \"bar\"", ErrUnknownSuffix},
+	{"invalid_arch", ParseOptions{Dialect: ArchDialect}, "unsupported arch \"bar\"", ErrInvalidArch},
+	{"multiple_pkgbase", ParseOptions{}, "key \"pkgbase\" can not occur after pkgbase or pkgname", ErrDuplicatePkgbase},
+	{"multiple_pkgname", ParseOptions{}, "pkgname \"foo1\" can not occur more than once", ErrDuplicatePkgname},
+	{"no_equals", ParseOptions{}, "Line does not contain =", ErrMissingEquals},
+	{"no_key", ParseOptions{}, "Key is empty", ErrEmptyKey},
+	{"no_file", ParseOptions{}, "Unable to read file", nil},
+	{"no_pkgbase", ParseOptions{}, "key \"pkgdesc\" can not occur before pkgbase or pkgname", ErrFieldBeforePkgbase},
+	{"no_pkgname", ParseOptions{}, "No pkgname field", ErrMissingPkgname},
+	{"no_arch", ParseOptions{Dialect: ArchDialect}, "No arch field", ErrMissingArch},
+	{"no_pkgrel", ParseOptions{Dialect: ArchDialect}, "No pkgrel field", ErrMissingPkgrel},
+	{"no_pkgver", ParseOptions{}, "No pkgver field", ErrMissingPkgver},
+	{"pkgname_before_pkgbase", ParseOptions{}, "key \"pkgname\" can not occur before pkgbase", ErrPkgnameBeforePkgbase},
+	{"unknown_key", ParseOptions{Strict: true}, "Unknown key \"foo\"", ErrUnknownKey},
 }
 
 func TestGoodSrcinfos(t *testing.T) {
@@ -720,15 +730,55 @@ func TestGoodSrcinfos(t *testing.T) {
 }
 
 func TestBadSrcinfos(t *testing.T) {
-	for _, name := range badSrcinfos {
-		path := filepath.Join(badSrcinfoDir, name)
-		_, err := ParseFile(path)
+	for _, bad := range badSrcinfos {
+		path := filepath.Join(badSrcinfoDir, bad.name)
+		_, err := ParseFileWithOptions(path, bad.opts)
 		if err == nil {
-			t.Errorf("%s parsed when it should have errored", name)
+			t.Errorf("%s parsed when it should have errored", bad.name)
+		} else if !strings.Contains(err.Error(), bad.err) {
+			t.Errorf("%s errored for the wrong reason: expected \"%s\" got \"%s\"", bad.name, bad.err, err)
+		} else if bad.kind != nil && !errors.Is(err, bad.kind) {
+			t.Errorf("%s error should be %v but was %#v", bad.name, bad.kind, err)
 		}
 	}
 }
 
+func TestNoValue(t *testing.T) {
+	srcinfo, err := ParseFile(filepath.Join(badSrcinfoDir, "no_value"))
+	if err != nil {
+		t.Fatalf("an empty value should be an empty override: %s", err)
+	}
+
+	expected := []ExtraField{{Key: "foo", ArchDistroString: ArchDistroString{Value: EmptyOverride}}}
+	if !reflect.DeepEqual(srcinfo.Extra, expected) {
+		t.Errorf("expected %#v got %#v", expected, srcinfo.Extra)
+	}
+}
+
+func TestUnknownKeyNotStrict(t *testing.T) {
+	path := filepath.Join(badSrcinfoDir, "unknown_key")
+	_, err := ParseFile(path)
+	if err != nil {
+		t.Errorf("unknown key should be ignored when not strict: %s", err)
+	}
+}
+
+func TestPacstallDefaults(t *testing.T) {
+	srcinfo, err := ParseFile(filepath.Join(badSrcinfoDir, "no_pkgrel"))
+	if err != nil {
+		t.Errorf("Error parsing %s: %s", "no_pkgrel", err)
+	} else if srcinfo.Pkgrel != "1" {
+		t.Errorf("missing pkgrel should default to 1 but was \"%s\"", srcinfo.Pkgrel)
+	}
+
+	srcinfo, err = ParseFile(filepath.Join(badSrcinfoDir, "no_arch"))
+	if err != nil {
+		t.Errorf("Error parsing %s: %s", "no_arch", err)
+	} else if !reflect.DeepEqual(srcinfo.Arch, []string{"any"}) {
+		t.Errorf("missing arch should default to any but was %v", srcinfo.Arch)
+	}
+}
+
 func TestSrcinfoData(t *testing.T) {
 	_, err := Parse(srcinfoData)
 	if err != nil {
@@ -762,3 +812,172 @@ func TestVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestExtra(t *testing.T) {
+	srcinfo, err := Parse(srcinfoData)
+	if err != nil {
+		t.Fatalf("Error parsing data: %s", err)
+	}
+
+	expected := []ExtraField{{Key: "options", ArchDistroString: ArchDistroString{Value: "!strip"}}}
+	if !reflect.DeepEqual(srcinfo.Extra, expected) {
+		t.Errorf("global extra fields do not match:\n\n%#v\n\n%#v", expected, srcinfo.Extra)
+	}
+
+	pkg, err := srcinfo.SplitPackage("linux-ck")
+	if err != nil {
+		t.Fatalf("could not get package %s: %s", "linux-ck", err)
+	}
+
+	expected = append(
+		[]ExtraField{{Key: "install", ArchDistroString: ArchDistroString{Value: "linux.install"}}},
+		expected...,
+	)
+	if !reflect.DeepEqual(pkg.Extra, expected) {
+		t.Errorf("split package extra fields do not match:\n\n%#v\n\n%#v", expected, pkg.Extra)
+	}
+}
+
+func TestDistroOverride(t *testing.T) {
+	srcinfo, err := ParseFile(filepath.Join(goodSrcinfoDir, "distro_override"))
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", "distro_override", err)
+	}
+
+	expected := map[string][]ArchDistroString{
+		// overriding depends replaces depends_jammy and depends_bookworm
+		"foo": {
+			{"", "", "g"},
+			{"x86_64", "", "d"},
+			{"x86_64", "jammy", "e"},
+		},
+		// likewise depends_x86_64 replaces depends_jammy_x86_64
+		"bar": {
+			{"", "jammy", "h"},
+			{"x86_64", "", "i"},
+			{"", "", "a"},
+			{"", "bookworm", "c"},
+		},
+		"baz": {
+			{"x86_64", "jammy", "j"},
+			{"", "", "a"},
+			{"", "jammy", "b"},
+			{"x86_64", "", "d"},
+		},
+	}
+
+	for _, pkg := range srcinfo.SplitPackages() {
+		if !reflect.DeepEqual(pkg.Depends, expected[pkg.Pkgname]) {
+			t.Errorf("%s: depends do not match:\n\n%#v\n\n%#v", pkg.Pkgname, expected[pkg.Pkgname], pkg.Depends)
+		}
+	}
+
+	pkg, err := srcinfo.SplitPackage("baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOpt := []ArchDistroString{{"", "", "k: for k support"}}
+	if !reflect.DeepEqual(pkg.OptDepends, expectedOpt) {
+		t.Errorf("optdepends do not match:\n\n%#v\n\n%#v", expectedOpt, pkg.OptDepends)
+	}
+}
+
+func TestEmptyOverride(t *testing.T) {
+	srcinfo, err := Parse(`pkgbase = foo
+	pkgver = 1
+	pkgdesc = base description
+	url = https://example.com
+	license = MIT
+	backup = etc/foo.conf
+	depends = a
+	install = foo.install
+
+pkgname = foo
+	pkgdesc =
+	url =
+	license =
+	backup =
+	depends =
+	install =
+
+pkgname = foo-bar
+	pkgdesc = bar description
+	depends = b
+
+pkgname = foo-baz`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := srcinfo.SplitPackage("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pkg.Pkgdesc != "" || pkg.URL != "" {
+		t.Errorf("cleared strings should be empty: %q %q", pkg.Pkgdesc, pkg.URL)
+	}
+
+	if len(pkg.License) != 0 || len(pkg.Backup) != 0 || len(pkg.Depends) != 0 || len(pkg.Extra) != 0 {
+		t.Errorf("cleared slices should be empty: %#v", pkg)
+	}
+
+	pkg, _ = srcinfo.SplitPackage("foo-baz")
+	if pkg.Pkgdesc != "base description" || !reflect.DeepEqual(pkg.License, []string{"MIT"}) {
+		t.Errorf("unset fields should use the package base: %#v", pkg)
+	}
+
+	inherited, err := Parse(`pkgbase = foo
+	pkgver = 1
+	depends =
+	depends_jammy = a
+	provides_jammy =
+	install =
+	conflicts = b
+
+pkgname = foo
+	conflicts = c`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err = inherited.SplitPackage("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(pkg.Depends, []ArchDistroString{{"", "jammy", "a"}}) || len(pkg.Provides) != 0 || len(pkg.Extra) != 0 {
+		t.Errorf("inherited empty overrides should be removed: %#v", pkg)
+	}
+
+	if !reflect.DeepEqual(pkg.Conflicts, []ArchDistroString{{"", "", "c"}}) {
+		t.Errorf("conflicts were not overridden: %#v", pkg.Conflicts)
+	}
+
+	tests := []struct {
+		pkgname    string
+		field      string
+		overridden bool
+		cleared    bool
+	}{
+		{"foo", "pkgdesc", true, true},
+		{"foo", "license", true, true},
+		{"foo", "depends", true, true},
+		{"foo", "install", true, true},
+		{"foo", "provides", false, false},
+		{"foo-bar", "pkgdesc", true, false},
+		{"foo-bar", "depends", true, false},
+		{"foo-bar", "url", false, false},
+		{"foo-baz", "pkgdesc", false, false},
+		{"foo", "pkgver", false, false},
+		{"missing", "pkgdesc", false, false},
+	}
+
+	for _, test := range tests {
+		overridden, cleared := srcinfo.IsOverridden(test.pkgname, test.field)
+		if overridden != test.overridden || cleared != test.cleared {
+			t.Errorf("%s %s: expected %v %v got %v %v", test.pkgname, test.field, test.overridden, test.cleared, overridden, cleared)
+		}
+	}
+}
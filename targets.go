@@ -0,0 +1,157 @@
+package srcinfo
+
+import (
+	"sort"
+	"strings"
+)
+
+// TargetPackages is a Target along with the packages of a srcinfo resolved
+// for it.
+type TargetPackages struct {
+	Target   Target
+	Verdict  Verdict // Why the target is compatible
+	Packages []ResolvedPackage
+}
+
+// Targets is TargetsWith using the Registry the srcinfo was parsed with, or
+// DefaultRegistry if there was none.
+func (si *Srcinfo) Targets() []TargetPackages {
+	if si.registry != nil {
+		return si.TargetsWith(si.registry)
+	}
+
+	return si.TargetsWith(DefaultRegistry())
+}
+
+// TargetsWith returns every target the srcinfo distinguishes between, with
+// the packages resolved for each, see Resolve.
+//
+// The targets are the cross product of every arch in the arch fields and
+// every distro used by the srcinfo. Distros are taken from the suffixes of
+// every field and from the entries of compatible that name a distro or a
+// codename, *:jammy naming the codename of any distro. A suffix or an entry
+// naming a codename is paired with its distro using reg, a suffix that is
+// neither a known distro nor a known codename is treated as a distro. The
+// target without any distro, standing for every other system, is included too.
+//
+// Targets that are not compatible, see CompatibleWith, or that no package
+// supports are left out. The targets are sorted by distro and codename for
+// each arch, in the order the arches are listed.
+func (si *Srcinfo) TargetsWith(reg *Registry) []TargetPackages {
+	var arches []string
+	seenArches := make(map[string]struct{})
+
+	addArches := func(values []string) {
+		for _, arch := range values {
+			if _, ok := seenArches[si.aliases.Canonical(arch)]; !ok && arch != EmptyOverride {
+				seenArches[si.aliases.Canonical(arch)] = struct{}{}
+				arches = append(arches, arch)
+			}
+		}
+	}
+
+	addArches(si.Arch)
+	for _, pkg := range si.Packages {
+		addArches(pkg.Arch)
+	}
+
+	distros := map[Target]struct{}{{}: {}}
+
+	addDistro := func(distro string) {
+		if distro == "" || distro == EmptyOverride {
+			return
+		}
+
+		t := Target{Distro: distro}
+		if name, codename, ok := strings.Cut(distro, "_"); ok && reg.hasCodename(name, codename) {
+			t = Target{Distro: name, Codename: codename}
+		} else if name, ok := reg.DistroOf(distro); ok && !reg.IsDistro(distro) {
+			t = Target{Distro: name, Codename: distro}
+		}
+
+		distros[t] = struct{}{}
+	}
+
+	addValues := func(values []ArchDistroString) {
+		for _, v := range values {
+			addDistro(v.Distro)
+		}
+	}
+
+	for _, field := range builtinFields {
+		if field.ads == nil {
+			continue
+		}
+
+		addValues(*field.ads(si, &si.Package))
+
+		if field.Scope == ScopePackage {
+			for n := range si.Packages {
+				addValues(*field.ads(si, &si.Packages[n]))
+			}
+		}
+	}
+
+	for _, pkg := range append([]Package{si.Package}, si.Packages...) {
+		for _, v := range pkg.Extra {
+			addDistro(v.Distro)
+		}
+	}
+
+	for _, rule := range si.Compatible {
+		distro, codename, _ := strings.Cut(rule, ":")
+		if distro == "" || rule == EmptyOverride {
+			continue
+		}
+
+		if codename == "*" {
+			codename = ""
+		}
+
+		if distro == "*" {
+			if codename == "" {
+				continue
+			}
+
+			// a codename of any distro, such as *:jammy
+			distro, _ = reg.DistroOf(codename)
+		}
+
+		distros[Target{Distro: distro, Codename: codename}] = struct{}{}
+	}
+
+	sorted := make([]Target, 0, len(distros))
+	for t := range distros {
+		sorted = append(sorted, t)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Distro != sorted[j].Distro {
+			return sorted[i].Distro < sorted[j].Distro
+		}
+
+		return sorted[i].Codename < sorted[j].Codename
+	})
+
+	var targets []TargetPackages
+
+	for _, arch := range arches {
+		for _, distro := range sorted {
+			t := Target{arch, distro.Distro, distro.Codename}
+
+			verdict := si.CompatibleWith(t)
+			if !verdict.Compatible {
+				continue
+			}
+
+			pkgs := si.Resolve(t)
+			if len(pkgs) == 0 {
+				continue
+			}
+
+			targets = append(targets, TargetPackages{t, verdict, pkgs})
+		}
+	}
+
+	return targets
+}
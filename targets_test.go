@@ -0,0 +1,102 @@
+package srcinfo
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTargets(t *testing.T) {
+	srcinfo, err := ParseFile(filepath.Join(goodSrcinfoDir, "distro_override"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := srcinfo.Targets()
+
+	expected := []Target{
+		{"x86_64", "", ""},
+		{"x86_64", "debian", "bookworm"},
+		{"x86_64", "ubuntu", "jammy"},
+		{"aarch64", "", ""},
+		{"aarch64", "debian", "bookworm"},
+		{"aarch64", "ubuntu", "jammy"},
+	}
+
+	if len(targets) != len(expected) {
+		t.Fatalf("expected %d targets got %#v", len(expected), targets)
+	}
+
+	for n, target := range targets {
+		if target.Target != expected[n] {
+			t.Errorf("expected target %#v got %#v", expected[n], target.Target)
+		}
+
+		if !reflect.DeepEqual(target.Packages, srcinfo.Resolve(target.Target)) {
+			t.Errorf("%#v: packages were not resolved for the target", target.Target)
+		}
+	}
+
+	if depends := targets[2].Packages[0].Depends; !reflect.DeepEqual(depends, []string{"g", "e"}) {
+		t.Errorf("unexpected depends for %#v: %v", targets[2].Target, depends)
+	}
+
+	srcinfo.Compatible = []string{"ubuntu:*", "debian:sid"}
+	srcinfo.Incompatible = []string{"*:jammy"}
+	srcinfo.Source = append(srcinfo.Source, ArchDistroString{"", "ubuntu_noble", "foo"})
+
+	expected = []Target{
+		{"x86_64", "debian", "sid"},
+		{"x86_64", "ubuntu", ""},
+		{"x86_64", "ubuntu", "noble"},
+		{"aarch64", "debian", "sid"},
+		{"aarch64", "ubuntu", ""},
+		{"aarch64", "ubuntu", "noble"},
+	}
+
+	targets = srcinfo.Targets()
+	if len(targets) != len(expected) {
+		t.Fatalf("expected %d targets got %#v", len(expected), targets)
+	}
+
+	for n, target := range targets {
+		if target.Target != expected[n] || !target.Verdict.Compatible {
+			t.Errorf("expected target %#v got %#v", expected[n], target)
+		}
+	}
+}
+
+func TestTargetsWildcardDistro(t *testing.T) {
+	srcinfo, err := Parse(`pkgbase = foo
+	pkgver = 1
+	arch = amd64
+	compatible = *:jammy
+pkgname = foo`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := srcinfo.Targets()
+	if len(targets) != 1 || targets[0].Target != (Target{"amd64", "ubuntu", "jammy"}) {
+		t.Errorf("expected the target amd64 ubuntu jammy got %#v", targets)
+	}
+}
+
+func TestTargetsRegistry(t *testing.T) {
+	reg := DefaultRegistry()
+	reg.AddDistro("pop", "cosmic")
+
+	srcinfo, err := ParseWithOptions(`pkgbase = foo
+	pkgver = 1
+	arch = amd64
+	depends_cosmic = a
+pkgname = foo`, ParseOptions{Registry: reg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := srcinfo.Targets()
+	if len(targets) != 2 || targets[1].Target != (Target{"amd64", "pop", "cosmic"}) {
+		t.Errorf("the registry the srcinfo was parsed with should be used, got %#v", targets)
+	}
+}
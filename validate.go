@@ -0,0 +1,176 @@
+package srcinfo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors that may be wrapped by a ValidationError. Use errors.Is to check for
+// them.
+var (
+	ErrInvalidName     = errors.New("invalid package name")
+	ErrInvalidVersion  = errors.New("invalid version")
+	ErrInvalidPriority = errors.New("invalid priority")
+)
+
+// Priorities are the values accepted for the priority field. They are the
+// Debian priorities plus "essential", which pacstall uses to mark a package
+// as essential.
+var Priorities = []string{"essential", "required", "important", "standard", "optional", "extra"}
+
+// ValidationError is a violation of naming or versioning policy found by
+// Validate.
+type ValidationError struct {
+	Pkgname  string   // The section the value was found in, empty for pkgbase
+	Field    string   // The field at fault, such as "pkgver"
+	Value    string   // The value at fault
+	Position Position // Where the value was found, the zero value if unknown
+	Err      error    // The kind of error, one of the ErrInvalid variables
+	ErrorStr string   // An error string
+}
+
+// Error returns an error string in the format
+// "Line <Line> Column <Column>: <ErrorStr>", the position being left out if it
+// is unknown.
+func (ve *ValidationError) Error() string {
+	if ve.Position.Line == 0 {
+		return ve.ErrorStr
+	}
+
+	return fmt.Sprintf("Line %d Column %d: %s", ve.Position.Line, ve.Position.Column, ve.ErrorStr)
+}
+
+// Unwrap returns the kind of error, allowing errors.Is to be used with the
+// ErrInvalid variables.
+func (ve *ValidationError) Unwrap() error {
+	return ve.Err
+}
+
+// Validate checks the srcinfo against Debian and pacstall policy:
+//
+//	pkgbase and every pkgname are at least two characters of lower case
+//	letters, digits and "+-.", starting with a letter or digit
+//	pkgver, pkgrel and epoch are valid as checked by Version.Validate
+//	pkgver does not contain "_", which Debian does not allow
+//	pkgrel is a number, optionally followed by a dot and another number
+//	priority, where given, is one of Priorities
+//
+// Each violation is returned as a *ValidationError joined with errors.Join.
+// Use ValidateWith to include the position of each value.
+func (si *Srcinfo) Validate() error {
+	return si.ValidateWith(nil)
+}
+
+// ValidateWith is Validate, setting the Position of each ValidationError from
+// the Positions filled in while parsing.
+func (si *Srcinfo) ValidateWith(pos Positions) error {
+	var errs []error
+
+	check := func(pkgname, field, value string, kind error, err error) {
+		if err == nil {
+			return
+		}
+
+		p, _ := pos.Lookup(pkgname, field, 0)
+		errs = append(errs, &ValidationError{
+			Pkgname:  pkgname,
+			Field:    field,
+			Value:    value,
+			Position: p,
+			Err:      kind,
+			ErrorStr: err.Error(),
+		})
+	}
+
+	check("", "pkgbase", si.Pkgbase, ErrInvalidName, validateName("pkgbase", si.Pkgbase))
+	check("", "pkgver", si.Pkgver, ErrInvalidVersion, validatePolicyPkgver(unset(si.Pkgver)))
+	check("", "pkgrel", si.Pkgrel, ErrInvalidVersion, validatePolicyPkgrel(unset(si.Pkgrel)))
+	check("", "epoch", si.Epoch, ErrInvalidVersion, validateEpoch(unset(si.Epoch)))
+	check("", "priority", si.Priority, ErrInvalidPriority, validatePriority(unset(si.Priority)))
+
+	for _, pkg := range si.Packages {
+		check(pkg.Pkgname, "pkgname", pkg.Pkgname, ErrInvalidName, validateName("pkgname", pkg.Pkgname))
+		check(pkg.Pkgname, "priority", pkg.Priority, ErrInvalidPriority, validatePriority(unset(pkg.Priority)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// unset turns an EmptyOverride into an empty string.
+func unset(value string) string {
+	if value == EmptyOverride {
+		return ""
+	}
+
+	return value
+}
+
+func validateName(field, name string) error {
+	if len(name) < 2 {
+		return fmt.Errorf("%s \"%s\" must be at least two characters", field, name)
+	}
+
+	if !isAlnum(name[0]) {
+		return fmt.Errorf("%s \"%s\" must start with a letter or digit", field, name)
+	}
+
+	for _, c := range []byte(name) {
+		if isAlpha(c) && c < 'a' {
+			return fmt.Errorf("%s \"%s\" must be lower case", field, name)
+		}
+
+		if !isAlnum(c) && !strings.ContainsRune("+-.", rune(c)) {
+			return fmt.Errorf("%s \"%s\" contains invalid character '%c'", field, name, c)
+		}
+	}
+
+	return nil
+}
+
+func validatePolicyPkgver(pkgver string) error {
+	if err := validatePkgver(pkgver); err != nil {
+		return err
+	}
+
+	if strings.Contains(pkgver, "_") {
+		return fmt.Errorf("pkgver \"%s\" must not contain '_'", pkgver)
+	}
+
+	return nil
+}
+
+func validatePolicyPkgrel(pkgrel string) error {
+	if err := validatePkgrel(pkgrel); err != nil || pkgrel == "" {
+		return err
+	}
+
+	major, minor, hasMinor := strings.Cut(pkgrel, ".")
+	if !isNumber(major) || (hasMinor && !isNumber(minor)) {
+		return fmt.Errorf("pkgrel \"%s\" is not a number", pkgrel)
+	}
+
+	return nil
+}
+
+func isNumber(str string) bool {
+	if str == "" {
+		return false
+	}
+
+	for _, c := range []byte(str) {
+		if !isDigit(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validatePriority(priority string) error {
+	if priority == "" || contains(Priorities, priority) {
+		return nil
+	}
+
+	return fmt.Errorf("priority \"%s\" is not one of %s", priority, strings.Join(Priorities, ", "))
+}
@@ -0,0 +1,150 @@
+package srcinfo
+
+import (
+	"errors"
+	"testing"
+)
+
+// validationErrors returns the ValidationErrors joined in err.
+func validationErrors(err error) []*ValidationError {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+
+	var errs []*ValidationError
+	for _, err := range joined.Unwrap() {
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			errs = append(errs, ve)
+		}
+	}
+
+	return errs
+}
+
+func TestValidate(t *testing.T) {
+	srcinfo, err := Parse(srcinfoData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srcinfo.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	srcinfo, err = Parse(`pkgbase = Foo
+	pkgver = 1.0-2
+	pkgrel = 1a
+	epoch = x
+	priority = urgent
+pkgname = foo
+	priority = optional
+pkgname = f
+pkgname = foo_bar`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = srcinfo.Validate()
+
+	errs := validationErrors(err)
+	if len(errs) == 0 {
+		t.Fatalf("expected ValidationErrors got %v", err)
+	}
+
+	expected := []struct {
+		pkgname string
+		field   string
+		kind    error
+	}{
+		{"", "pkgbase", ErrInvalidName},
+		{"", "pkgver", ErrInvalidVersion},
+		{"", "pkgrel", ErrInvalidVersion},
+		{"", "epoch", ErrInvalidVersion},
+		{"", "priority", ErrInvalidPriority},
+		{"f", "pkgname", ErrInvalidName},
+		{"foo_bar", "pkgname", ErrInvalidName},
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors got %d: %s", len(expected), len(errs), err)
+	}
+
+	for n, e := range expected {
+		if errs[n].Pkgname != e.pkgname || errs[n].Field != e.field || !errors.Is(errs[n], e.kind) {
+			t.Errorf("expected %s %s %v got %#v", e.pkgname, e.field, e.kind, errs[n])
+		}
+
+		if errs[n].Position.Line != 0 {
+			t.Errorf("%s: position should be unknown", errs[n].Field)
+		}
+	}
+
+	if errs[0].Error() != "pkgbase \"Foo\" must be lower case" {
+		t.Errorf("unexpected error string: %s", errs[0].Error())
+	}
+}
+
+func TestValidateWith(t *testing.T) {
+	positions := Positions{}
+
+	srcinfo, err := ParseWithOptions(`pkgbase = foo
+	pkgver = 1:0
+	pkgrel = 1.1
+pkgname = foo
+	priority = extra
+pkgname = Foo-Bar
+	priority = low`, ParseOptions{Positions: positions})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = srcinfo.ValidateWith(positions)
+
+	errs := validationErrors(err)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors got %v", err)
+	}
+
+	expected := []struct {
+		field string
+		pos   Position
+	}{
+		{"pkgver", Position{2, 11, 14}},
+		{"pkgname", Position{6, 11, 18}},
+		{"priority", Position{7, 13, 16}},
+	}
+
+	for n, e := range expected {
+		if errs[n].Field != e.field || errs[n].Position != e.pos {
+			t.Errorf("expected %s at %v got %s at %v", e.field, e.pos, errs[n].Field, errs[n].Position)
+		}
+	}
+
+	if errs[0].Error() != "Line 2 Column 11: pkgver \"1:0\" contains invalid characters" {
+		t.Errorf("unexpected error string: %s", errs[0].Error())
+	}
+}
+
+func TestValidateUnderscore(t *testing.T) {
+	srcinfo, err := Parse(`pkgbase = foo
+	pkgver = 1.0_beta
+pkgname = foo`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := srcinfo.ParsedVersion(); err != nil {
+		t.Errorf("pkgver with _ should be a valid version: %s", err)
+	}
+
+	err = srcinfo.Validate()
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("expected ErrInvalidVersion got %v", err)
+	}
+
+	if err.Error() != "pkgver \"1.0_beta\" must not contain '_'" {
+		t.Errorf("unexpected error string: %s", err.Error())
+	}
+}
@@ -0,0 +1,413 @@
+package srcinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version is a package version in the form [epoch:]pkgver[-pkgrel].
+//
+// Versions are compared using the rules of dpkg, as pacstall installs onto
+// dpkg based systems. VerCmp compares using the rules of pacman instead.
+type Version struct {
+	Epoch  string
+	Pkgver string
+	Pkgrel string
+}
+
+// ParsedVersion returns the epoch, pkgver and pkgrel of the srcinfo as a
+// Version, after checking they are valid.
+func (si *Srcinfo) ParsedVersion() (Version, error) {
+	v := Version{si.Epoch, si.Pkgver, si.Pkgrel}
+	return v, v.Validate()
+}
+
+// ParseVersion parses a version string in the form [epoch:]pkgver[-pkgrel].
+// The epoch ends at the first colon and the pkgrel starts after the last
+// hyphen.
+func ParseVersion(str string) (Version, error) {
+	var v Version
+	rest := str
+
+	if epoch, after, ok := strings.Cut(rest, ":"); ok {
+		v.Epoch = epoch
+		rest = after
+
+		if v.Epoch == "" {
+			return Version{}, fmt.Errorf("Invalid version \"%s\": epoch is empty", str)
+		}
+	}
+
+	if n := strings.LastIndexByte(rest, '-'); n != -1 {
+		v.Pkgrel = rest[n+1:]
+		rest = rest[:n]
+
+		if v.Pkgrel == "" {
+			return Version{}, fmt.Errorf("Invalid version \"%s\": pkgrel is empty", str)
+		}
+	}
+
+	v.Pkgver = rest
+
+	if err := v.Validate(); err != nil {
+		return Version{}, fmt.Errorf("Invalid version \"%s\": %s", str, err.Error())
+	}
+
+	return v, nil
+}
+
+// String formats the version as [epoch:]pkgver[-pkgrel].
+func (v Version) String() string {
+	str := v.Pkgver
+
+	if v.Epoch != "" {
+		str = v.Epoch + ":" + str
+	}
+
+	if v.Pkgrel != "" {
+		str += "-" + v.Pkgrel
+	}
+
+	return str
+}
+
+// Validate checks that the epoch is a number and that pkgver and pkgrel are
+// only made up of alphanumerics and the characters ".+~_". pkgver must not be
+// empty.
+//
+// "_" is accepted as pacman allows it, but Debian does not and dpkg-deb
+// refuses to build such a package. Srcinfo.Validate checks against Debian
+// policy and rejects it.
+func (v Version) Validate() error {
+	if err := validateEpoch(v.Epoch); err != nil {
+		return err
+	}
+
+	if err := validatePkgver(v.Pkgver); err != nil {
+		return err
+	}
+
+	return validatePkgrel(v.Pkgrel)
+}
+
+func validateEpoch(epoch string) error {
+	for _, c := range []byte(epoch) {
+		if !isDigit(c) {
+			return fmt.Errorf("epoch \"%s\" is not a number", epoch)
+		}
+	}
+
+	return nil
+}
+
+func validatePkgver(pkgver string) error {
+	if pkgver == "" {
+		return fmt.Errorf("pkgver is empty")
+	}
+
+	if !validVersionPart(pkgver) {
+		return fmt.Errorf("pkgver \"%s\" contains invalid characters", pkgver)
+	}
+
+	return nil
+}
+
+func validatePkgrel(pkgrel string) error {
+	if !validVersionPart(pkgrel) {
+		return fmt.Errorf("pkgrel \"%s\" contains invalid characters", pkgrel)
+	}
+
+	return nil
+}
+
+func validVersionPart(str string) bool {
+	for _, c := range []byte(str) {
+		if !isAlnum(c) && !strings.ContainsRune(".+~_", rune(c)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare compares two versions using dpkg's rules. It returns -1 if v is
+// older than other, 1 if it is newer and 0 if they are equal.
+//
+// The epoch is compared numerically, a missing epoch being 0. pkgver and
+// pkgrel are then compared as alternating runs of non-digits and digits,
+// where ~ sorts before everything, even the end of the string, and letters
+// sort before other characters.
+func (v Version) Compare(other Version) int {
+	if c := verrevcmp(v.Epoch, other.Epoch); c != 0 {
+		return c
+	}
+
+	if c := verrevcmp(v.Pkgver, other.Pkgver); c != 0 {
+		return c
+	}
+
+	return verrevcmp(v.Pkgrel, other.Pkgrel)
+}
+
+// VerCmp compares two versions using pacman's rules, as used by vercmp. It
+// returns -1 if v is older than other, 1 if it is newer and 0 if they are
+// equal. The pkgrels are only compared if both versions have one.
+func (v Version) VerCmp(other Version) int {
+	epoch, otherEpoch := v.Epoch, other.Epoch
+	if epoch == "" {
+		epoch = "0"
+	}
+
+	if otherEpoch == "" {
+		otherEpoch = "0"
+	}
+
+	if c := rpmvercmp(epoch, otherEpoch); c != 0 {
+		return c
+	}
+
+	if c := rpmvercmp(v.Pkgver, other.Pkgver); c != 0 {
+		return c
+	}
+
+	if v.Pkgrel == "" || other.Pkgrel == "" {
+		return 0
+	}
+
+	return rpmvercmp(v.Pkgrel, other.Pkgrel)
+}
+
+// CompareVersions parses two version strings and compares them using dpkg's
+// rules, see Version.Compare.
+func CompareVersions(a, b string) (int, error) {
+	va, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return va.Compare(vb), nil
+}
+
+// VerCmp compares two version strings using pacman's rules, see
+// Version.VerCmp. Like pacman's vercmp any string is accepted.
+func VerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	return parseEVR(a).VerCmp(parseEVR(b))
+}
+
+// parseEVR splits a version the same way pacman does, without validation.
+func parseEVR(str string) Version {
+	var v Version
+
+	digits := 0
+	for digits < len(str) && isDigit(str[digits]) {
+		digits++
+	}
+
+	rest := str
+	if digits < len(str) && str[digits] == ':' {
+		v.Epoch = str[:digits]
+		rest = str[digits+1:]
+	}
+
+	if n := strings.LastIndexByte(rest, '-'); n != -1 {
+		v.Pkgrel = rest[n+1:]
+		rest = rest[:n]
+	}
+
+	v.Pkgver = rest
+	return v
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || isAlpha(c)
+}
+
+// order is the weight of a character in a non-digit run for dpkg.
+func order(str string, n int) int {
+	if n >= len(str) {
+		return 0
+	}
+
+	c := str[n]
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+// verrevcmp is dpkg's comparison of a pkgver or pkgrel.
+func verrevcmp(a, b string) int {
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := order(a, i), order(b, j)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+
+			i++
+			j++
+		}
+
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+
+	return 0
+}
+
+// rpmvercmp is pacman's comparison of an epoch, pkgver or pkgrel.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		startA, startB := i, j
+
+		for i < len(a) && !isAlnum(a[i]) {
+			i++
+		}
+
+		for j < len(b) && !isAlnum(b[j]) {
+			j++
+		}
+
+		if i == len(a) || j == len(b) {
+			break
+		}
+
+		// a longer separator sorts higher
+		if i-startA != j-startB {
+			if i-startA < j-startB {
+				return -1
+			}
+
+			return 1
+		}
+
+		startA, startB = i, j
+		isNum := isDigit(a[i])
+
+		if isNum {
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+		} else {
+			for i < len(a) && isAlpha(a[i]) {
+				i++
+			}
+
+			for j < len(b) && isAlpha(b[j]) {
+				j++
+			}
+		}
+
+		segA, segB := a[startA:i], b[startB:j]
+
+		// numeric segments are always newer than alpha segments
+		if segB == "" {
+			if isNum {
+				return 1
+			}
+
+			return -1
+		}
+
+		if isNum {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+
+			if len(segA) != len(segB) {
+				if len(segA) > len(segB) {
+					return 1
+				}
+
+				return -1
+			}
+		}
+
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+
+	if i == len(a) && j == len(b) {
+		return 0
+	}
+
+	// a remaining alpha segment never beats an empty string
+	if (i == len(a) && !isAlpha(b[j])) || (i < len(a) && isAlpha(a[i])) {
+		return -1
+	}
+
+	return 1
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,152 @@
+package srcinfo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	good := []struct {
+		str     string
+		version Version
+	}{
+		{"1.0", Version{"", "1.0", ""}},
+		{"1.0-1", Version{"", "1.0", "1"}},
+		{"2:1.0-1", Version{"2", "1.0", "1"}},
+		{"1.0~rc1+git_r12-2.1", Version{"", "1.0~rc1+git_r12", "2.1"}},
+	}
+
+	for _, test := range good {
+		v, err := ParseVersion(test.str)
+		if err != nil {
+			t.Errorf("%s: %s", test.str, err)
+			continue
+		}
+
+		if v != test.version {
+			t.Errorf("%s: expected %#v got %#v", test.str, test.version, v)
+		}
+
+		if v.String() != test.str {
+			t.Errorf("%s: does not round trip: %s", test.str, v)
+		}
+	}
+
+	bad := []string{"", ":1.0", "a:1.0", "1.0-", "-1", "1.0 beta", "1:2:3", "1.0-1-a/b"}
+
+	for _, str := range bad {
+		if _, err := ParseVersion(str); err == nil {
+			t.Errorf("%s: should have errored", str)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		dpkg int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0-0", 0},
+		{"1.0-1", "1.0-2", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~", "1.0", -1},
+		{"1.0", "1.0a", -1},
+		{"1.0a", "1.0+", -1},
+		{"1.0+b1", "1.0", 1},
+		{"1:0.1", "2.0", 1},
+		{"0:1.0", "1.0", 0},
+		{"01.002", "1.2", 0},
+		{"1.0-1ubuntu1", "1.0-1", 1},
+	}
+
+	for _, test := range tests {
+		c, err := CompareVersions(test.a, test.b)
+		if err != nil {
+			t.Errorf("%s %s: %s", test.a, test.b, err)
+			continue
+		}
+
+		if c != test.dpkg {
+			t.Errorf("%s %s: expected %d got %d", test.a, test.b, test.dpkg, c)
+		}
+
+		c, _ = CompareVersions(test.b, test.a)
+		if c != -test.dpkg {
+			t.Errorf("%s %s: expected %d got %d", test.b, test.a, -test.dpkg, c)
+		}
+	}
+}
+
+func TestVerCmp(t *testing.T) {
+	// from pacman's vercmptest.sh
+	tests := []struct {
+		a, b   string
+		result int
+	}{
+		{"1.5.0", "1.5.0", 0},
+		{"1.5.1", "1.5.0", 1},
+		{"1.5.1", "1.5", 1},
+		{"1.5.0-1", "1.5.0-1", 0},
+		{"1.5.0-1", "1.5.0-2", -1},
+		{"1.5.0-1", "1.5.1-1", -1},
+		{"1.5.0-2", "1.5.1-1", -1},
+		{"1.5-1", "1.5", 0},
+		{"1.5b-1", "1.5-1", -1},
+		{"1.5b", "1.5", -1},
+		{"1.5b-1", "1.5", -1},
+		{"1.5b", "1.5.1", -1},
+		{"1.0a", "1.0alpha", -1},
+		{"1.0alpha", "1.0b", -1},
+		{"1.0b", "1.0beta", -1},
+		{"1.0beta", "1.0rc", -1},
+		{"1.0rc", "1.0", -1},
+		{"1.5.a", "1.5", 1},
+		{"1.5.b", "1.5.a", 1},
+		{"1.5.1", "1.5.b", 1},
+		{"1.5.b-1", "1.5.b", 0},
+		{"1.5-1", "1.5.b", -1},
+		{"2.0", "2_0", 0},
+		{"2.0_a", "2_0.a", 0},
+		{"2.0a", "2.0.a", -1},
+		{"2___a", "2_a", 1},
+		{"0:1.0", "0:1.0", 0},
+		{"0:1.0", "0:1.1", -1},
+		{"1:1.0", "0:1.0", 1},
+		{"1:1.0", "0:1.1", 1},
+		{"1:1.0", "2:1.1", -1},
+		{"0:1.0", "1.0", 0},
+		{"0:1.0", "1.1", -1},
+		{"1:1.0", "1.0", 1},
+		{"1.0", "1.0-1", 0},
+		{"1:1.0-1", "1:1.0", 0},
+	}
+
+	for _, test := range tests {
+		if c := VerCmp(test.a, test.b); c != test.result {
+			t.Errorf("%s %s: expected %d got %d", test.a, test.b, test.result, c)
+		}
+
+		if c := VerCmp(test.b, test.a); c != -test.result {
+			t.Errorf("%s %s: expected %d got %d", test.b, test.a, -test.result, c)
+		}
+	}
+}
+
+func TestParsedVersion(t *testing.T) {
+	srcinfo, err := ParseFile(filepath.Join(goodSrcinfoDir, "stockfish"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := srcinfo.ParsedVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.String() != srcinfo.Version() {
+		t.Errorf("expected %s got %s", srcinfo.Version(), v)
+	}
+}